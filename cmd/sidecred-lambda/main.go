@@ -88,14 +88,17 @@ func runFunc(configBucket *string) func(*sidecred.Sidecred, sidecred.StateBacken
 				return failure(ctx, cfg.Namespace(), fmt.Errorf("failed to load state: %s", err))
 			}
 
-			if err := s.Process(ctx, cfg, state); err != nil {
-				return failure(ctx, cfg.Namespace(), err)
-			}
+			// The state is saved even if processing fails, since credentials may have been created for other requests.
+			processErr := s.Process(ctx, cfg, state)
 
 			if err := backend.Save(ctx, event.StatePath, state); err != nil {
 				return failure(ctx, cfg.Namespace(), fmt.Errorf("failed to save state: %s", err))
 			}
 
+			if processErr != nil {
+				return failure(ctx, cfg.Namespace(), processErr)
+			}
+
 			stats := eventctx.GetStats(ctx)
 			eventctx.GetLogger(ctx).Info(fmt.Sprintf("processing '%s' done", cfg.Namespace()),
 				zap.Int("calls_to_github", stats.CallsToGithub),
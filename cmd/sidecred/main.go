@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/alecthomas/kingpin"
 	"go.uber.org/zap"
@@ -11,7 +12,9 @@ import (
 	"github.com/telia-oss/sidecred"
 	"github.com/telia-oss/sidecred/config"
 	"github.com/telia-oss/sidecred/eventctx"
+	"github.com/telia-oss/sidecred/githubrotator"
 	"github.com/telia-oss/sidecred/internal/cli"
+	"github.com/telia-oss/sidecred/provider/github"
 )
 
 var version string
@@ -24,7 +27,12 @@ func main() {
 	)
 	cli.AddRunCommand(app, runFunc(configPath, statePath), nil, nil).Default()
 
-	validate := app.Command("validate", "Validate a sidecred config.")
+	var (
+		validate                    = app.Command("validate", "Validate a sidecred config.")
+		githubProviderEnabled       = validate.Flag("github-provider-enabled", "Validate Github access token permissions against the Github App(s)").Bool()
+		githubProviderIntegrationID = validate.Flag("github-provider-integration-id", "Github Apps integration ID").String()
+		githubProviderPrivateKey    = validate.Flag("github-provider-private-key", "Github apps private key").String()
+	)
 	validate.Action(func(_ *kingpin.ParseContext) error {
 		b, err := os.ReadFile(*configPath)
 		if err != nil {
@@ -34,7 +42,25 @@ func main() {
 		if err != nil {
 			app.Fatalf("failed to parse config: %s", err)
 		}
-		if err := cfg.Validate(); err != nil {
+		var providers []sidecred.Provider
+		if *githubProviderEnabled {
+			providers = append(providers, github.New(
+				githubrotator.New(&githubrotator.Config{
+					IntegrationIDs: strings.Split(*githubProviderIntegrationID, ","),
+					PrivateKeys:    strings.Split(*githubProviderPrivateKey, ","),
+					Logger:         zap.NewNop(),
+				}),
+			))
+		}
+		s, err := sidecred.New(providers, nil, 0)
+		if err != nil {
+			app.Fatalf("initialize sidecred: %s", err)
+		}
+		warnings, err := s.Validate(context.Background(), cfg)
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stdout, "warning: %s\n", w)
+		}
+		if err != nil {
 			app.Fatalf("validate: %s", err)
 		}
 		return nil
@@ -66,14 +92,17 @@ func runFunc(cfg, statePath *string) func(*sidecred.Sidecred, sidecred.StateBack
 			return fmt.Errorf("failed to load state: %s", err)
 		}
 
-		if err := s.Process(ctx, cfg, state); err != nil {
-			return err
-		}
+		// The state is saved even if processing fails, since credentials may have been created for other requests.
+		processErr := s.Process(ctx, cfg, state)
 
 		if err := backend.Save(ctx, *statePath, state); err != nil {
 			return fmt.Errorf("failed to save state: %s", err)
 		}
 
+		if processErr != nil {
+			return processErr
+		}
+
 		stats := eventctx.GetStats(ctx)
 		eventctx.GetLogger(ctx).Info(fmt.Sprintf("processing '%s' done", cfg.Namespace()),
 			zap.Int("calls_to_github", stats.CallsToGithub),
@@ -8,9 +8,15 @@ import (
 
 	"github.com/telia-oss/sidecred"
 	"github.com/telia-oss/sidecred/provider/artifactory"
+	"github.com/telia-oss/sidecred/provider/database"
+	"github.com/telia-oss/sidecred/provider/docker"
 	"github.com/telia-oss/sidecred/provider/github"
+	"github.com/telia-oss/sidecred/provider/jwt"
 	"github.com/telia-oss/sidecred/provider/random"
+	"github.com/telia-oss/sidecred/provider/ssh"
 	"github.com/telia-oss/sidecred/provider/sts"
+	"github.com/telia-oss/sidecred/provider/vault"
+	"github.com/telia-oss/sidecred/provider/x509"
 )
 
 // Parse a YAML (or JSON) representation of sidecred.Config.
@@ -78,7 +84,7 @@ func (c *v1) Validate() error {
 	stores := make(map[string]struct{}, len(c.CredentialStores))
 	for i, s := range c.CredentialStores {
 		switch s.Type {
-		case sidecred.Inprocess, sidecred.SSM, sidecred.SecretsManager, sidecred.GithubSecrets, sidecred.GithubDependabotSecrets:
+		case sidecred.Inprocess, sidecred.SSM, sidecred.SecretsManager, sidecred.GithubSecrets, sidecred.GithubDependabotSecrets, sidecred.GithubEnvironmentSecrets, sidecred.GithubOrganizationSecrets, sidecred.GithubCodespacesSecrets, sidecred.Kubernetes, sidecred.VaultSecrets, sidecred.GitlabVariables, sidecred.File:
 		default:
 			return fmt.Errorf("stores[%d]: unknown type %q", i, string(s.Type))
 		}
@@ -195,10 +201,24 @@ func parseProviderConfig(t sidecred.CredentialType, config json.RawMessage) (sid
 		c = &github.AccessTokenRequestConfig{}
 	case sidecred.GithubDeployKey:
 		c = &github.DeployKeyRequestConfig{}
+	case sidecred.GithubWebhookSecret:
+		c = &github.WebhookSecretRequestConfig{}
 	case sidecred.ArtifactoryAccessToken:
 		c = &artifactory.RequestConfig{}
 	case sidecred.Randomized:
 		c = &random.RequestConfig{}
+	case sidecred.SSHCertificate:
+		c = &ssh.RequestConfig{}
+	case sidecred.X509Certificate:
+		c = &x509.RequestConfig{}
+	case sidecred.VaultSecret:
+		c = &vault.RequestConfig{}
+	case sidecred.DatabasePassword:
+		c = &database.RequestConfig{}
+	case sidecred.DockerConfig:
+		c = &docker.RequestConfig{}
+	case sidecred.JWTToken:
+		c = &jwt.RequestConfig{}
 	default:
 		return nil, fmt.Errorf("unknown type %q", string(t))
 	}
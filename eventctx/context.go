@@ -13,6 +13,8 @@ var (
 	statsKey  = struct{}{}
 )
 
+type namespaceKey struct{}
+
 type Stats struct {
 	CallsToGithub int
 }
@@ -50,3 +52,12 @@ func GetStats(ctx context.Context) *Stats {
 func SetStats(ctx context.Context, stats *Stats) context.Context {
 	return context.WithValue(ctx, statsKey, stats)
 }
+
+func GetNamespace(ctx context.Context) string {
+	namespace, _ := ctx.Value(namespaceKey{}).(string)
+	return namespace
+}
+
+func SetNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/google/go-github/v45/github"
 	"github.com/telia-oss/githubapp"
@@ -12,6 +13,8 @@ import (
 //counterfeiter:generate -o fakes/app.go . App
 type App interface {
 	CreateInstallationToken(owner string, repositories []string, permissions *githubapp.Permissions) (*githubapp.Token, error)
+	GetPermissions(ctx context.Context) (*githubapp.Permissions, error)
+	ListInstallations(ctx context.Context) ([]string, error)
 }
 
 //counterfeiter:generate -o fakes/appfactory.go . AppFactory
@@ -39,7 +42,27 @@ func (defaultAppFactory) Create(integrationID, privateKey string) (App, error) {
 		return nil, fmt.Errorf("")
 	}
 
-	return githubapp.New(client), nil
+	apps, ok := client.(*github.AppsService)
+	if !ok {
+		return nil, fmt.Errorf("unexpected apps client: %T", client)
+	}
+
+	return &defaultApp{App: githubapp.New(client), client: apps}, nil
+}
+
+// defaultApp extends githubapp.App with the parts of the Apps API that
+// require authentication as the App itself (JWT).
+type defaultApp struct {
+	*githubapp.App
+	client *github.AppsService
+}
+
+func (a *defaultApp) GetPermissions(ctx context.Context) (*githubapp.Permissions, error) {
+	app, _, err := a.client.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return (*githubapp.Permissions)(app.GetPermissions()), nil
 }
 
 type defaultRateLimitClient struct{}
@@ -47,3 +70,24 @@ type defaultRateLimitClient struct{}
 func (defaultRateLimitClient) GetTokenRateLimits(ctx context.Context, token string) (*github.RateLimits, *github.Response, error) {
 	return githubapp.NewInstallationClient(token).V3.RateLimits(ctx)
 }
+
+func (a *defaultApp) ListInstallations(ctx context.Context) ([]string, error) {
+	var (
+		owners      []string
+		listOptions = &github.ListOptions{PerPage: 100}
+	)
+	for {
+		installations, response, err := a.client.ListInstallations(ctx, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range installations {
+			owners = append(owners, strings.ToLower(i.GetAccount().GetLogin()))
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		listOptions.Page = response.NextPage
+	}
+	return owners, nil
+}
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/telia-oss/githubapp"
@@ -24,6 +25,32 @@ type FakeApp struct {
 		result1 *githubapp.Token
 		result2 error
 	}
+	GetPermissionsStub        func(context.Context) (*githubapp.Permissions, error)
+	getPermissionsMutex       sync.RWMutex
+	getPermissionsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getPermissionsReturns struct {
+		result1 *githubapp.Permissions
+		result2 error
+	}
+	getPermissionsReturnsOnCall map[int]struct {
+		result1 *githubapp.Permissions
+		result2 error
+	}
+	ListInstallationsStub        func(context.Context) ([]string, error)
+	listInstallationsMutex       sync.RWMutex
+	listInstallationsArgsForCall []struct {
+		arg1 context.Context
+	}
+	listInstallationsReturns struct {
+		result1 []string
+		result2 error
+	}
+	listInstallationsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -99,11 +126,143 @@ func (fake *FakeApp) CreateInstallationTokenReturnsOnCall(i int, result1 *github
 	}{result1, result2}
 }
 
+func (fake *FakeApp) GetPermissions(arg1 context.Context) (*githubapp.Permissions, error) {
+	fake.getPermissionsMutex.Lock()
+	ret, specificReturn := fake.getPermissionsReturnsOnCall[len(fake.getPermissionsArgsForCall)]
+	fake.getPermissionsArgsForCall = append(fake.getPermissionsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetPermissionsStub
+	fakeReturns := fake.getPermissionsReturns
+	fake.recordInvocation("GetPermissions", []interface{}{arg1})
+	fake.getPermissionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeApp) GetPermissionsCallCount() int {
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	return len(fake.getPermissionsArgsForCall)
+}
+
+func (fake *FakeApp) GetPermissionsCalls(stub func(context.Context) (*githubapp.Permissions, error)) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = stub
+}
+
+func (fake *FakeApp) GetPermissionsArgsForCall(i int) context.Context {
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	argsForCall := fake.getPermissionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeApp) GetPermissionsReturns(result1 *githubapp.Permissions, result2 error) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = nil
+	fake.getPermissionsReturns = struct {
+		result1 *githubapp.Permissions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApp) GetPermissionsReturnsOnCall(i int, result1 *githubapp.Permissions, result2 error) {
+	fake.getPermissionsMutex.Lock()
+	defer fake.getPermissionsMutex.Unlock()
+	fake.GetPermissionsStub = nil
+	if fake.getPermissionsReturnsOnCall == nil {
+		fake.getPermissionsReturnsOnCall = make(map[int]struct {
+			result1 *githubapp.Permissions
+			result2 error
+		})
+	}
+	fake.getPermissionsReturnsOnCall[i] = struct {
+		result1 *githubapp.Permissions
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApp) ListInstallations(arg1 context.Context) ([]string, error) {
+	fake.listInstallationsMutex.Lock()
+	ret, specificReturn := fake.listInstallationsReturnsOnCall[len(fake.listInstallationsArgsForCall)]
+	fake.listInstallationsArgsForCall = append(fake.listInstallationsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.ListInstallationsStub
+	fakeReturns := fake.listInstallationsReturns
+	fake.recordInvocation("ListInstallations", []interface{}{arg1})
+	fake.listInstallationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeApp) ListInstallationsCallCount() int {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	return len(fake.listInstallationsArgsForCall)
+}
+
+func (fake *FakeApp) ListInstallationsCalls(stub func(context.Context) ([]string, error)) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = stub
+}
+
+func (fake *FakeApp) ListInstallationsArgsForCall(i int) context.Context {
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
+	argsForCall := fake.listInstallationsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeApp) ListInstallationsReturns(result1 []string, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	fake.listInstallationsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeApp) ListInstallationsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listInstallationsMutex.Lock()
+	defer fake.listInstallationsMutex.Unlock()
+	fake.ListInstallationsStub = nil
+	if fake.listInstallationsReturnsOnCall == nil {
+		fake.listInstallationsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listInstallationsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeApp) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createInstallationTokenMutex.RLock()
 	defer fake.createInstallationTokenMutex.RUnlock()
+	fake.getPermissionsMutex.RLock()
+	defer fake.getPermissionsMutex.RUnlock()
+	fake.listInstallationsMutex.RLock()
+	defer fake.listInstallationsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/go-github/v45/github"
@@ -16,6 +17,7 @@ import (
 
 const (
 	defaultRateLimitCutoff = 50
+	permissionsCacheTTL    = 1 * time.Hour
 )
 
 type Config struct {
@@ -27,10 +29,12 @@ type Config struct {
 }
 
 type app struct {
-	app            App
-	integrationID  string
-	token          *githubapp.Token
-	rateLimitError *github.RateLimitError
+	app                  App
+	integrationID        string
+	token                *githubapp.Token
+	rateLimitError       *github.RateLimitError
+	permissions          *githubapp.Permissions
+	permissionsUpdatedAt time.Time
 }
 
 func (app app) hasZeroRateLimit() bool {
@@ -141,6 +145,50 @@ func (r *Rotator) createInstallationToken(ctx context.Context, owner string, rep
 	return nil, fmt.Errorf("unable to retrieve token")
 }
 
+// GetPermissions returns the permissions granted to each of the configured Github Apps, keyed by
+// integration ID. The permissions are cached to avoid calling the Github API for every request.
+func (r *Rotator) GetPermissions(ctx context.Context) (map[string]*githubapp.Permissions, error) {
+	permissions := make(map[string]*githubapp.Permissions, len(r.apps))
+	for i := range r.apps {
+		a := &r.apps[i]
+		if a.permissions == nil || time.Since(a.permissionsUpdatedAt) > permissionsCacheTTL {
+			r.logger.Debug("get app permissions", zap.String("app", a.integrationID))
+
+			eventctx.GetStats(ctx).IncGithubCalls()
+			p, err := a.app.GetPermissions(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("get permissions (app='%s'): %w", a.integrationID, err)
+			}
+			a.permissions, a.permissionsUpdatedAt = p, time.Now()
+		}
+		permissions[a.integrationID] = a.permissions
+	}
+	return permissions, nil
+}
+
+// ListInstallations returns the (lowercased) owners where any of the configured Github Apps are installed.
+func (r *Rotator) ListInstallations(ctx context.Context) ([]string, error) {
+	seen := make(map[string]struct{})
+	for _, a := range r.apps {
+		r.logger.Debug("list app installations", zap.String("app", a.integrationID))
+
+		eventctx.GetStats(ctx).IncGithubCalls()
+		owners, err := a.app.ListInstallations(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list installations (app='%s'): %w", a.integrationID, err)
+		}
+		for _, o := range owners {
+			seen[o] = struct{}{}
+		}
+	}
+	owners := make([]string, 0, len(seen))
+	for o := range seen {
+		owners = append(owners, o)
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
+
 func (r *Rotator) rotate() {
 	tmp := r.apps[0]
 	for i := 0; i < (len(r.apps) - 1); i++ {
@@ -92,6 +92,77 @@ func TestRotator_CreateInstallationToken(t *testing.T) {
 	assert.Expect(err).To(BeNil())
 }
 
+func TestRotator_GetPermissions(t *testing.T) {
+	var (
+		logger = testLogger()
+		assert = NewGomegaWithT(t)
+	)
+
+	appA := &fakes.FakeApp{}
+	appA.GetPermissionsReturns(&githubapp.Permissions{Contents: github.String("write")}, nil)
+
+	appB := &fakes.FakeApp{}
+	appB.GetPermissionsReturnsOnCall(0, nil, errorUnexpected)
+	appB.GetPermissionsReturnsOnCall(1, &githubapp.Permissions{Contents: github.String("read")}, nil)
+
+	appFactory := returnsAppFactory(map[int]fakeAppFactoryResults{
+		0: {appA, nil},
+		1: {appB, nil},
+	})
+
+	rotator := githubrotator.New(&githubrotator.Config{
+		IntegrationIDs: []string{"App0", "App1"},
+		PrivateKeys:    []string{"Key0", "Key1"},
+		Logger:         logger,
+		OptAppFactory:  appFactory,
+	})
+
+	// App 1 returns an error, which is propagated
+	permissions, err := rotator.GetPermissions(eventctx.TestContext(t))
+	assert.Expect(err).To(HaveOccurred())
+	assert.Expect(permissions).To(BeNil())
+
+	// App 0 is cached, App 1 is retried
+	permissions, err = rotator.GetPermissions(eventctx.TestContext(t))
+	assert.Expect(err).To(BeNil())
+	assert.Expect(permissions).To(HaveLen(2))
+	assert.Expect(*permissions["App0"].Contents).To(Equal("write"))
+	assert.Expect(*permissions["App1"].Contents).To(Equal("read"))
+
+	// Both apps are cached
+	_, err = rotator.GetPermissions(eventctx.TestContext(t))
+	assert.Expect(err).To(BeNil())
+	assert.Expect(appA.GetPermissionsCallCount()).To(Equal(1))
+	assert.Expect(appB.GetPermissionsCallCount()).To(Equal(2))
+}
+
+func TestRotator_ListInstallations(t *testing.T) {
+	var (
+		logger = testLogger()
+		assert = NewGomegaWithT(t)
+	)
+
+	appA := &fakes.FakeApp{}
+	appA.ListInstallationsReturns([]string{"telia-oss", "itsdalmo"}, nil)
+
+	appB := &fakes.FakeApp{}
+	appB.ListInstallationsReturns([]string{"telia-oss", "telia-test"}, nil)
+
+	rotator := githubrotator.New(&githubrotator.Config{
+		IntegrationIDs: []string{"App0", "App1"},
+		PrivateKeys:    []string{"Key0", "Key1"},
+		Logger:         logger,
+		OptAppFactory: returnsAppFactory(map[int]fakeAppFactoryResults{
+			0: {appA, nil},
+			1: {appB, nil},
+		}),
+	})
+
+	owners, err := rotator.ListInstallations(eventctx.TestContext(t))
+	assert.Expect(err).To(BeNil())
+	assert.Expect(owners).To(Equal([]string{"itsdalmo", "telia-oss", "telia-test"}))
+}
+
 type fakeAppResults struct {
 	result1 *githubapp.Token
 	result2 error
@@ -6,8 +6,10 @@ require (
 	github.com/alecthomas/kingpin v2.2.6+incompatible
 	github.com/aws/aws-lambda-go v1.13.3
 	github.com/aws/aws-sdk-go v1.28.7
+	github.com/go-sql-driver/mysql v1.6.0
 	github.com/google/go-github/v45 v45.2.0
 	github.com/jfrog/jfrog-client-go v0.7.0
+	github.com/lib/pq v1.10.7
 	github.com/maxbrunsfeld/counterfeiter/v6 v6.4.1
 	github.com/stretchr/testify v1.7.0
 	github.com/telia-oss/aws-env v1.0.2
@@ -12,66 +12,143 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	cryptossh "golang.org/x/crypto/ssh"
 
 	"github.com/telia-oss/sidecred"
 	"github.com/telia-oss/sidecred/backend/file"
 	"github.com/telia-oss/sidecred/backend/s3"
 	"github.com/telia-oss/sidecred/githubrotator"
+	"github.com/telia-oss/sidecred/internal/gitlab"
+	"github.com/telia-oss/sidecred/internal/kubernetes"
+	vaultapi "github.com/telia-oss/sidecred/internal/vault"
 	"github.com/telia-oss/sidecred/provider/artifactory"
+	"github.com/telia-oss/sidecred/provider/database"
+	"github.com/telia-oss/sidecred/provider/docker"
 	"github.com/telia-oss/sidecred/provider/github"
+	"github.com/telia-oss/sidecred/provider/jwt"
 	"github.com/telia-oss/sidecred/provider/random"
+	"github.com/telia-oss/sidecred/provider/ssh"
 	"github.com/telia-oss/sidecred/provider/sts"
+	"github.com/telia-oss/sidecred/provider/vault"
+	"github.com/telia-oss/sidecred/provider/x509"
+	filestore "github.com/telia-oss/sidecred/store/file"
 	githubstore "github.com/telia-oss/sidecred/store/github"
+	gitlabstore "github.com/telia-oss/sidecred/store/gitlab"
 	"github.com/telia-oss/sidecred/store/inprocess"
+	kubernetesstore "github.com/telia-oss/sidecred/store/kubernetes"
 	"github.com/telia-oss/sidecred/store/secretsmanager"
 	"github.com/telia-oss/sidecred/store/ssm"
+	vaultstore "github.com/telia-oss/sidecred/store/vault"
 )
 
 // Type definitions that allow us to reuse the CLI (flags and setup) between binaries, and
 // also so we can pass in test fakes during testing.
 type (
 	runFunc          func(*sidecred.Sidecred, sidecred.StateBackend, sidecred.RunConfig) error
-	awsClientFactory func() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI)
+	awsClientFactory func() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI, docker.ECRAPI)
 	loggerFactory    func(bool) (*zap.Logger, error)
 )
 
 // AddRunCommand configures a kingpin.Application to run sidecred.
 func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClientFactory, newLogger loggerFactory) *kingpin.CmdClause {
 	var (
-		cmd                                 = app.Command("run", "Run sidecred.")
-		randomProviderRotationInterval      = cmd.Flag("random-provider-rotation-interval", "Rotation interval for the random provider").Default("168h").Duration()
-		stsProviderEnabled                  = cmd.Flag("sts-provider-enabled", "Enable the STS provider").Bool()
-		stsProviderExternalID               = cmd.Flag("sts-provider-external-id", "External ID for the STS Provider").String()
-		stsProviderSessionDuration          = cmd.Flag("sts-provider-session-duration", "Session duration for STS credentials").Default("1h").Duration()
-		githubProviderEnabled               = cmd.Flag("github-provider-enabled", "Enable the Github provider").Bool()
-		githubProviderIntegrationID         = cmd.Flag("github-provider-integration-id", "Github Apps integration ID").String()
-		githubProviderPrivateKey            = cmd.Flag("github-provider-private-key", "Github apps private key").String()
-		githubProviderKeyRotationInterval   = cmd.Flag("github-provider-key-rotation-interval", "Rotation interval for deploy keys").Default("168h").Duration()
-		artifactoryProviderEnabled          = cmd.Flag("artifactory-provider-enabled", "Enable the Artifactory provider").Bool()
-		artifactoryProviderHostname         = cmd.Flag("artifactory-provider-hostname", "Hostname for the Artifactory Provider").String()
-		artifactoryProviderUsername         = cmd.Flag("artifactory-provider-username", "Username for the Artifactory Provider").String()
-		artifactoryProviderPassword         = cmd.Flag("artifactory-provider-password", "Password for the Artifactory Provider").String()
-		artifactoryProviderAccessToken      = cmd.Flag("artifactory-provider-access-token", "Access token for the Artifactory Provider").String()
-		artifactoryProviderAPIKey           = cmd.Flag("artifactory-provider-api-key", "API key for the Artifactory Provider").String()
-		artifactoryProviderSessionDuration  = cmd.Flag("artifactory-provider-session-duration", "Session duration for artifactory tokens").Default("1h").Duration()
-		inprocessStoreSecretTemplate        = cmd.Flag("inprocess-store-secret-template", "Path template to use for the inprocess store").Default("{{ .Namespace }}.{{ .Name }}").String()
-		secretsManagerStoreEnabled          = cmd.Flag("secrets-manager-store-enabled", "Enable AWS Secrets Manager store for secrets").Bool()
-		secretsManagerStoreSecretTemplate   = cmd.Flag("secrets-manager-store-secret-template", "Path template to use for the secrets manager store").Default("/{{ .Namespace }}/{{ .Name }}").String()
-		ssmStoreEnabled                     = cmd.Flag("ssm-store-enabled", "Enable AWS SSM Parameter store for secrets").Bool()
-		ssmStoreSecretTemplate              = cmd.Flag("ssm-store-secret-template", "Path template to use for SSM Parameter store").Default("/{{ .Namespace }}/{{ .Name }}").String()
-		ssmStoreKMSKeyID                    = cmd.Flag("ssm-store-kms-key-id", "KMS key to use for encrypting secrets stored in SSM Parameter store").String()
-		githubStoreEnabled                  = cmd.Flag("github-store-enabled", "Enable Github repository secrets store").Bool()
-		githubStoreSecretTemplate           = cmd.Flag("github-store-secret-template", "Template to use for naming Github repository secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
-		githubStoreIntegrationID            = cmd.Flag("github-store-integration-id", "Github Apps integration ID").String()
-		githubStorePrivateKey               = cmd.Flag("github-store-private-key", "Github apps private key").String()
-		githubDependabotStoreEnabled        = cmd.Flag("github-dependabot-store-enabled", "Enable Github repository Dependabot secrets store").Bool()
-		githubDependabotStoreSecretTemplate = cmd.Flag("github-dependabot-store-secret-template", "Template to use for naming Github repository Dependabot secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
-		githubDependabotStoreIntegrationID  = cmd.Flag("github-dependabot-store-integration-id", "Github Apps integration ID").String()
-		githubDependabotStorePrivateKey     = cmd.Flag("github-dependabot-store-private-key", "Github apps private key").String()
-		stateBackend                        = cmd.Flag("state-backend", "Backend to use for storing state").Required().String()
-		s3BackendBucket                     = cmd.Flag("s3-backend-bucket", "Bucket name to use for the S3 state backend").String()
-		rotationWindow                      = cmd.Flag("rotation-window", "A window in time (duration) where sidecred should rotate credentials prior to their expiration").Default("10m").Duration()
-		debug                               = cmd.Flag("debug", "Enable debug logging").Bool()
+		cmd                                   = app.Command("run", "Run sidecred.")
+		randomProviderRotationInterval        = cmd.Flag("random-provider-rotation-interval", "Rotation interval for the random provider").Default("168h").Duration()
+		stsProviderEnabled                    = cmd.Flag("sts-provider-enabled", "Enable the STS provider").Bool()
+		stsProviderExternalID                 = cmd.Flag("sts-provider-external-id", "External ID for the STS Provider").String()
+		stsProviderSessionDuration            = cmd.Flag("sts-provider-session-duration", "Session duration for STS credentials").Default("1h").Duration()
+		githubProviderEnabled                 = cmd.Flag("github-provider-enabled", "Enable the Github provider").Bool()
+		githubProviderIntegrationID           = cmd.Flag("github-provider-integration-id", "Github Apps integration ID").String()
+		githubProviderPrivateKey              = cmd.Flag("github-provider-private-key", "Github apps private key").String()
+		githubProviderKeyRotationInterval     = cmd.Flag("github-provider-key-rotation-interval", "Rotation interval for deploy keys").Default("168h").Duration()
+		githubProviderSecretRotation          = cmd.Flag("github-provider-secret-rotation-interval", "Rotation interval for webhook secrets").Default("168h").Duration()
+		githubProviderReconcileDeployKeys     = cmd.Flag("github-provider-reconcile-deploy-keys", "Remove stale deploy keys created by sidecred that are not tracked in state").Bool()
+		githubProviderRevokeAccessTokens      = cmd.Flag("github-provider-revoke-access-tokens", "Revoke access tokens when they are rotated (stores the token in state)").Bool()
+		artifactoryProviderEnabled            = cmd.Flag("artifactory-provider-enabled", "Enable the Artifactory provider").Bool()
+		artifactoryProviderHostname           = cmd.Flag("artifactory-provider-hostname", "Hostname for the Artifactory Provider").String()
+		artifactoryProviderUsername           = cmd.Flag("artifactory-provider-username", "Username for the Artifactory Provider").String()
+		artifactoryProviderPassword           = cmd.Flag("artifactory-provider-password", "Password for the Artifactory Provider").String()
+		artifactoryProviderAccessToken        = cmd.Flag("artifactory-provider-access-token", "Access token for the Artifactory Provider").String()
+		artifactoryProviderAPIKey             = cmd.Flag("artifactory-provider-api-key", "API key for the Artifactory Provider").String()
+		artifactoryProviderSessionDuration    = cmd.Flag("artifactory-provider-session-duration", "Session duration for artifactory tokens").Default("1h").Duration()
+		artifactoryProviderMaxDuration        = cmd.Flag("artifactory-provider-max-session-duration", "Maximum duration that can be requested for artifactory tokens (0 for no limit)").Default("0s").Duration()
+		sshProviderEnabled                    = cmd.Flag("ssh-provider-enabled", "Enable the SSH certificate provider").Bool()
+		sshProviderCAPrivateKey               = cmd.Flag("ssh-provider-ca-private-key", "Private key for the SSH certificate authority").String()
+		sshProviderCertificateDuration        = cmd.Flag("ssh-provider-certificate-duration", "Default duration for SSH certificates").Default("1h").Duration()
+		sshProviderMaxCertificateDuration     = cmd.Flag("ssh-provider-max-certificate-duration", "Maximum duration that can be requested for SSH certificates").Default("24h").Duration()
+		x509ProviderEnabled                   = cmd.Flag("x509-provider-enabled", "Enable the X.509 certificate provider").Bool()
+		x509ProviderCACertificate             = cmd.Flag("x509-provider-ca-certificate", "Certificate (PEM) for the X.509 certificate authority, optionally followed by its chain").String()
+		x509ProviderCAPrivateKey              = cmd.Flag("x509-provider-ca-private-key", "Private key (PEM) for the X.509 certificate authority").String()
+		x509ProviderCertificateTTL            = cmd.Flag("x509-provider-certificate-ttl", "Default TTL for X.509 certificates").Default("24h").Duration()
+		x509ProviderMaxCertificateTTL         = cmd.Flag("x509-provider-max-certificate-ttl", "Maximum TTL that can be requested for X.509 certificates").Default("720h").Duration()
+		vaultProviderEnabled                  = cmd.Flag("vault-provider-enabled", "Enable the Vault provider").Bool()
+		vaultProviderAddress                  = cmd.Flag("vault-provider-address", "Address for the Vault provider (e.g. https://vault.example.com:8200)").String()
+		vaultProviderToken                    = cmd.Flag("vault-provider-token", "Token for the Vault provider").String()
+		vaultProviderNamespace                = cmd.Flag("vault-provider-namespace", "Vault Enterprise namespace for the Vault provider").String()
+		vaultProviderDefaultLeaseDuration     = cmd.Flag("vault-provider-default-lease-duration", "Expiration for Vault secrets that do not have a lease").Default("1h").Duration()
+		databaseProviderEnabled               = cmd.Flag("database-provider-enabled", "Enable the database password provider").Bool()
+		databaseProviderConnections           = cmd.Flag("database-provider-connections", "Admin connections for the database provider as name=url (postgres:// or mysql://)").StringMap()
+		databaseProviderRotationInterval      = cmd.Flag("database-provider-rotation-interval", "Rotation interval for database passwords").Default("168h").Duration()
+		dockerProviderEnabled                 = cmd.Flag("docker-provider-enabled", "Enable the Docker config provider").Bool()
+		dockerProviderECREnabled              = cmd.Flag("docker-provider-ecr-enabled", "Enable ECR authorization tokens for the Docker config provider").Bool()
+		dockerProviderStaticCredentials       = cmd.Flag("docker-provider-static-credentials", "Static registry credentials for the Docker config provider as name=username:password").StringMap()
+		dockerProviderRotationInterval        = cmd.Flag("docker-provider-rotation-interval", "Rotation interval for Docker configs that only use static credentials").Default("168h").Duration()
+		jwtProviderEnabled                    = cmd.Flag("jwt-provider-enabled", "Enable the JWT provider").Bool()
+		jwtProviderPrivateKey                 = cmd.Flag("jwt-provider-private-key", "Private key (PEM) used to sign tokens").String()
+		jwtProviderIssuer                     = cmd.Flag("jwt-provider-issuer", "Default issuer for tokens").String()
+		jwtProviderKeyID                      = cmd.Flag("jwt-provider-key-id", "Key ID to include in the header of tokens").String()
+		jwtProviderTokenTTL                   = cmd.Flag("jwt-provider-token-ttl", "Default TTL for tokens").Default("1h").Duration()
+		jwtProviderMaxTokenTTL                = cmd.Flag("jwt-provider-max-token-ttl", "Maximum TTL that can be requested for tokens").Default("24h").Duration()
+		inprocessStoreSecretTemplate          = cmd.Flag("inprocess-store-secret-template", "Path template to use for the inprocess store").Default("{{ .Namespace }}.{{ .Name }}").String()
+		secretsManagerStoreEnabled            = cmd.Flag("secrets-manager-store-enabled", "Enable AWS Secrets Manager store for secrets").Bool()
+		secretsManagerStoreSecretTemplate     = cmd.Flag("secrets-manager-store-secret-template", "Path template to use for the secrets manager store").Default("/{{ .Namespace }}/{{ .Name }}").String()
+		ssmStoreEnabled                       = cmd.Flag("ssm-store-enabled", "Enable AWS SSM Parameter store for secrets").Bool()
+		ssmStoreSecretTemplate                = cmd.Flag("ssm-store-secret-template", "Path template to use for SSM Parameter store").Default("/{{ .Namespace }}/{{ .Name }}").String()
+		ssmStoreKMSKeyID                      = cmd.Flag("ssm-store-kms-key-id", "KMS key to use for encrypting secrets stored in SSM Parameter store").String()
+		githubStoreEnabled                    = cmd.Flag("github-store-enabled", "Enable Github repository secrets store").Bool()
+		githubStoreSecretTemplate             = cmd.Flag("github-store-secret-template", "Template to use for naming Github repository secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
+		githubStoreIntegrationID              = cmd.Flag("github-store-integration-id", "Github Apps integration ID").String()
+		githubStorePrivateKey                 = cmd.Flag("github-store-private-key", "Github apps private key").String()
+		githubDependabotStoreEnabled          = cmd.Flag("github-dependabot-store-enabled", "Enable Github repository Dependabot secrets store").Bool()
+		githubDependabotStoreSecretTemplate   = cmd.Flag("github-dependabot-store-secret-template", "Template to use for naming Github repository Dependabot secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
+		githubDependabotStoreIntegrationID    = cmd.Flag("github-dependabot-store-integration-id", "Github Apps integration ID").String()
+		githubDependabotStorePrivateKey       = cmd.Flag("github-dependabot-store-private-key", "Github apps private key").String()
+		githubEnvironmentStoreEnabled         = cmd.Flag("github-environment-store-enabled", "Enable Github environment secrets store").Bool()
+		githubEnvironmentStoreSecretTemplate  = cmd.Flag("github-environment-store-secret-template", "Template to use for naming Github environment secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
+		githubEnvironmentStoreIntegrationID   = cmd.Flag("github-environment-store-integration-id", "Github Apps integration ID").String()
+		githubEnvironmentStorePrivateKey      = cmd.Flag("github-environment-store-private-key", "Github apps private key").String()
+		githubOrganizationStoreEnabled        = cmd.Flag("github-organization-store-enabled", "Enable Github organization secrets store").Bool()
+		githubOrganizationStoreSecretTemplate = cmd.Flag("github-organization-store-secret-template", "Template to use for naming Github organization secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
+		githubOrganizationStoreIntegrationID  = cmd.Flag("github-organization-store-integration-id", "Github Apps integration ID").String()
+		githubOrganizationStorePrivateKey     = cmd.Flag("github-organization-store-private-key", "Github apps private key").String()
+		githubCodespacesStoreEnabled          = cmd.Flag("github-codespaces-store-enabled", "Enable Github repository Codespaces secrets store").Bool()
+		githubCodespacesStoreSecretTemplate   = cmd.Flag("github-codespaces-store-secret-template", "Template to use for naming Github repository Codespaces secrets").Default("{{ .Namespace}}_{{ .Name }}").String()
+		githubCodespacesStoreIntegrationID    = cmd.Flag("github-codespaces-store-integration-id", "Github Apps integration ID").String()
+		githubCodespacesStorePrivateKey       = cmd.Flag("github-codespaces-store-private-key", "Github apps private key").String()
+		kubernetesStoreEnabled                = cmd.Flag("kubernetes-store-enabled", "Enable Kubernetes secrets store").Bool()
+		kubernetesStoreSecretTemplate         = cmd.Flag("kubernetes-store-secret-template", "Template to use for naming Kubernetes secrets").Default("{{ .Namespace }}-{{ .Name }}").String()
+		kubernetesStoreNamespace              = cmd.Flag("kubernetes-store-namespace", "Default Kubernetes namespace for secrets").Default("default").String()
+		kubernetesStoreAddress                = cmd.Flag("kubernetes-store-address", "Address of the Kubernetes API server (defaults to in-cluster configuration)").String()
+		kubernetesStoreToken                  = cmd.Flag("kubernetes-store-token", "Bearer token for the Kubernetes API server").String()
+		kubernetesStoreCACertificate          = cmd.Flag("kubernetes-store-ca-certificate", "Certificate authority (PEM) for the Kubernetes API server").String()
+		vaultStoreEnabled                     = cmd.Flag("vault-store-enabled", "Enable Vault KV (version 2) secrets store").Bool()
+		vaultStoreAddress                     = cmd.Flag("vault-store-address", "Address for the Vault store (e.g. https://vault.example.com:8200)").String()
+		vaultStoreToken                       = cmd.Flag("vault-store-token", "Token for the Vault store").String()
+		vaultStoreNamespace                   = cmd.Flag("vault-store-namespace", "Vault Enterprise namespace for the Vault store").String()
+		vaultStoreMount                       = cmd.Flag("vault-store-mount", "Mount path of the KV secrets engine for the Vault store").Default("secret").String()
+		vaultStoreSecretTemplate              = cmd.Flag("vault-store-secret-template", "Path template to use for the Vault store").Default("{{ .Namespace }}/{{ .Name }}").String()
+		vaultStoreHardDelete                  = cmd.Flag("vault-store-hard-delete", "Destroy (rather than delete) secret versions in the Vault store").Bool()
+		gitlabStoreEnabled                    = cmd.Flag("gitlab-store-enabled", "Enable GitLab CI/CD variables store").Bool()
+		gitlabStoreAddress                    = cmd.Flag("gitlab-store-address", "Address of the GitLab instance").Default("https://gitlab.com").String()
+		gitlabStoreToken                      = cmd.Flag("gitlab-store-token", "Access token for the GitLab store").String()
+		gitlabStoreSecretTemplate             = cmd.Flag("gitlab-store-secret-template", "Template to use for naming GitLab CI/CD variables").Default("{{ .Namespace }}_{{ .Name }}").String()
+		fileStoreEnabled                      = cmd.Flag("file-store-enabled", "Enable local filesystem store for secrets").Bool()
+		fileStoreDirectory                    = cmd.Flag("file-store-directory", "Directory to write secrets to for the file store").String()
+		fileStoreSecretTemplate               = cmd.Flag("file-store-secret-template", "Path template to use for the file store").Default("{{ .Namespace }}/{{ .Name }}").String()
+		stateBackend                          = cmd.Flag("state-backend", "Backend to use for storing state").Required().String()
+		s3BackendBucket                       = cmd.Flag("s3-backend-bucket", "Bucket name to use for the S3 state backend").String()
+		rotationWindow                        = cmd.Flag("rotation-window", "A window in time (duration) where sidecred should rotate credentials prior to their expiration").Default("10m").Duration()
+		debug                                 = cmd.Flag("debug", "Enable debug logging").Bool()
 	)
 
 	cmd.Action(func(_ *kingpin.ParseContext) error {
@@ -88,11 +165,10 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 		defer logger.Sync()
 
 		providers := []sidecred.Provider{random.New(
-			time.Now().UnixNano(),
 			random.WithRotationInterval(*randomProviderRotationInterval),
 		)}
 		if *stsProviderEnabled {
-			_, client, _, _ := newAWSClient()
+			_, client, _, _, _ := newAWSClient()
 			providers = append(providers, sts.New(client,
 				sts.WithExternalID(*stsProviderExternalID),
 				sts.WithSessionDuration(*stsProviderSessionDuration),
@@ -106,8 +182,12 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 					Logger:         logger,
 				}),
 				github.WithDeployKeyRotationInterval(*githubProviderKeyRotationInterval),
+				github.WithWebhookSecretRotationInterval(*githubProviderSecretRotation),
+				github.WithDeployKeyReconciliation(*githubProviderReconcileDeployKeys),
+				github.WithAccessTokenRevocation(*githubProviderRevokeAccessTokens),
 			))
 		}
+		var artifactoryProvider sidecred.Provider
 		if *artifactoryProviderEnabled {
 			client, err := artifactory.NewClient(
 				*artifactoryProviderHostname,
@@ -118,8 +198,84 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 			if err != nil {
 				logger.Fatal("initialize artifactory", zap.Error(err))
 			}
-			providers = append(providers, artifactory.New(client,
+			artifactoryProvider = artifactory.New(client,
 				artifactory.WithSessionDuration(*artifactoryProviderSessionDuration),
+				artifactory.WithMaxSessionDuration(*artifactoryProviderMaxDuration),
+			)
+			providers = append(providers, artifactoryProvider)
+		}
+
+		if *sshProviderEnabled {
+			ca, err := cryptossh.ParsePrivateKey([]byte(*sshProviderCAPrivateKey))
+			if err != nil {
+				logger.Fatal("parse ssh ca private key", zap.Error(err))
+			}
+			providers = append(providers, ssh.New(ca,
+				ssh.WithCertificateDuration(*sshProviderCertificateDuration),
+				ssh.WithMaxCertificateDuration(*sshProviderMaxCertificateDuration),
+			))
+		}
+
+		if *x509ProviderEnabled {
+			ca, err := x509.ParseCA([]byte(*x509ProviderCACertificate), []byte(*x509ProviderCAPrivateKey))
+			if err != nil {
+				logger.Fatal("parse x509 ca", zap.Error(err))
+			}
+			providers = append(providers, x509.New(ca,
+				x509.WithCertificateTTL(*x509ProviderCertificateTTL),
+				x509.WithMaxCertificateTTL(*x509ProviderMaxCertificateTTL),
+			))
+		}
+
+		if *jwtProviderEnabled {
+			key, err := jwt.ParsePrivateKey([]byte(*jwtProviderPrivateKey))
+			if err != nil {
+				logger.Fatal("parse jwt private key", zap.Error(err))
+			}
+			providers = append(providers, jwt.New(key,
+				jwt.WithIssuer(*jwtProviderIssuer),
+				jwt.WithKeyID(*jwtProviderKeyID),
+				jwt.WithTokenTTL(*jwtProviderTokenTTL),
+				jwt.WithMaxTokenTTL(*jwtProviderMaxTokenTTL),
+			))
+		}
+
+		if *vaultProviderEnabled {
+			providers = append(providers, vault.New(
+				vaultapi.NewClient(*vaultProviderAddress, *vaultProviderToken, vaultapi.WithNamespace(*vaultProviderNamespace)),
+				vault.WithDefaultLeaseDuration(*vaultProviderDefaultLeaseDuration),
+			))
+		}
+
+		if *databaseProviderEnabled {
+			connections := make(map[string]*database.Connection, len(*databaseProviderConnections))
+			for name, url := range *databaseProviderConnections {
+				conn, err := database.Open(url)
+				if err != nil {
+					logger.Fatal("open database connection", zap.String("name", name), zap.Error(err))
+				}
+				connections[name] = conn
+			}
+			providers = append(providers, database.New(connections,
+				database.WithRotationInterval(*databaseProviderRotationInterval),
+			))
+		}
+
+		if *dockerProviderEnabled {
+			var client docker.ECRAPI
+			if *dockerProviderECREnabled {
+				_, _, _, _, client = newAWSClient()
+			}
+			for name, c := range *dockerProviderStaticCredentials {
+				if !strings.Contains(c, ":") {
+					logger.Fatal("static docker credentials must be username:password", zap.String("name", name))
+				}
+			}
+			providers = append(providers, docker.New(
+				docker.WithArtifactoryProvider(artifactoryProvider),
+				docker.WithECRClient(client),
+				docker.WithStaticCredentials(*dockerProviderStaticCredentials),
+				docker.WithRotationInterval(*dockerProviderRotationInterval),
 			))
 		}
 
@@ -127,13 +283,13 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 			inprocess.WithSecretTemplate(*inprocessStoreSecretTemplate),
 		)}
 		if *secretsManagerStoreEnabled {
-			_, _, _, client := newAWSClient()
+			_, _, _, client, _ := newAWSClient()
 			stores = append(stores, secretsmanager.New(client,
 				secretsmanager.WithSecretTemplate(*secretsManagerStoreSecretTemplate),
 			))
 		}
 		if *ssmStoreEnabled {
-			_, _, client, _ := newAWSClient()
+			_, _, client, _, _ := newAWSClient()
 			stores = append(stores, ssm.New(client,
 				ssm.WithSecretTemplate(*ssmStoreSecretTemplate),
 				ssm.WithKMSKeyID(*ssmStoreKMSKeyID),
@@ -161,12 +317,86 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 			))
 		}
 
+		if *githubEnvironmentStoreEnabled {
+			stores = append(stores, githubstore.NewEnvironmentStore(
+				githubrotator.New(&githubrotator.Config{
+					IntegrationIDs: strings.Split(*githubEnvironmentStoreIntegrationID, ","),
+					PrivateKeys:    strings.Split(*githubEnvironmentStorePrivateKey, ","),
+					Logger:         logger,
+				}),
+				githubstore.WithSecretTemplate(*githubEnvironmentStoreSecretTemplate),
+			))
+		}
+
+		if *githubOrganizationStoreEnabled {
+			stores = append(stores, githubstore.NewOrganizationStore(
+				githubrotator.New(&githubrotator.Config{
+					IntegrationIDs: strings.Split(*githubOrganizationStoreIntegrationID, ","),
+					PrivateKeys:    strings.Split(*githubOrganizationStorePrivateKey, ","),
+					Logger:         logger,
+				}),
+				githubstore.WithSecretTemplate(*githubOrganizationStoreSecretTemplate),
+			))
+		}
+
+		if *githubCodespacesStoreEnabled {
+			stores = append(stores, githubstore.NewCodespacesStore(
+				githubrotator.New(&githubrotator.Config{
+					IntegrationIDs: strings.Split(*githubCodespacesStoreIntegrationID, ","),
+					PrivateKeys:    strings.Split(*githubCodespacesStorePrivateKey, ","),
+					Logger:         logger,
+				}),
+				githubstore.WithSecretTemplate(*githubCodespacesStoreSecretTemplate),
+			))
+		}
+
+		if *kubernetesStoreEnabled {
+			client := kubernetes.NewClient(*kubernetesStoreAddress, *kubernetesStoreToken,
+				kubernetes.WithCACertificate([]byte(*kubernetesStoreCACertificate)),
+			)
+			if *kubernetesStoreAddress == "" {
+				client, err = kubernetes.NewInClusterClient()
+				if err != nil {
+					logger.Fatal("initialize kubernetes client", zap.Error(err))
+				}
+			}
+			stores = append(stores, kubernetesstore.New(client,
+				kubernetesstore.WithSecretTemplate(*kubernetesStoreSecretTemplate),
+				kubernetesstore.WithNamespace(*kubernetesStoreNamespace),
+			))
+		}
+
+		if *vaultStoreEnabled {
+			stores = append(stores, vaultstore.New(
+				vaultapi.NewClient(*vaultStoreAddress, *vaultStoreToken, vaultapi.WithNamespace(*vaultStoreNamespace)),
+				vaultstore.WithMount(*vaultStoreMount),
+				vaultstore.WithSecretTemplate(*vaultStoreSecretTemplate),
+				vaultstore.WithHardDelete(*vaultStoreHardDelete),
+			))
+		}
+
+		if *gitlabStoreEnabled {
+			stores = append(stores, gitlabstore.New(
+				gitlab.NewClient(*gitlabStoreAddress, *gitlabStoreToken),
+				gitlabstore.WithSecretTemplate(*gitlabStoreSecretTemplate),
+			))
+		}
+
+		if *fileStoreEnabled {
+			if *fileStoreDirectory == "" {
+				logger.Fatal("directory must be specified for the file store")
+			}
+			stores = append(stores, filestore.New(*fileStoreDirectory,
+				filestore.WithSecretTemplate(*fileStoreSecretTemplate),
+			))
+		}
+
 		var backend sidecred.StateBackend
 		switch *stateBackend {
 		case "file":
 			backend = file.New()
 		case "s3":
-			client, _, _, _ := newAWSClient()
+			client, _, _, _, _ := newAWSClient()
 			backend = s3.New(client, *s3BackendBucket)
 		default:
 			logger.Fatal("unknown state backend", zap.String("backend", *stateBackend))
@@ -184,7 +414,7 @@ func AddRunCommand(app *kingpin.Application, run runFunc, newAWSClient awsClient
 	return cmd
 }
 
-func defaultAWSClientFactory() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI) {
+func defaultAWSClientFactory() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI, docker.ECRAPI) {
 	var (
 		sess *session.Session
 		err  error
@@ -196,7 +426,7 @@ func defaultAWSClientFactory() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager
 			panic(fmt.Errorf("create aws session: %s", err))
 		}
 	})
-	return s3.NewClient(sess), sts.NewClient(sess), ssm.NewClient(sess), secretsmanager.NewClient(sess)
+	return s3.NewClient(sess), sts.NewClient(sess), ssm.NewClient(sess), secretsmanager.NewClient(sess), docker.NewECRClient(sess)
 }
 
 func defaultLogger(debug bool) (*zap.Logger, error) {
@@ -19,6 +19,8 @@ import (
 	"github.com/telia-oss/sidecred/config"
 	"github.com/telia-oss/sidecred/eventctx"
 	"github.com/telia-oss/sidecred/internal/cli"
+	"github.com/telia-oss/sidecred/provider/docker"
+	"github.com/telia-oss/sidecred/provider/docker/dockerfakes"
 	"github.com/telia-oss/sidecred/provider/sts"
 	"github.com/telia-oss/sidecred/provider/sts/stsfakes"
 	"github.com/telia-oss/sidecred/store/secretsmanager"
@@ -27,8 +29,8 @@ import (
 	"github.com/telia-oss/sidecred/store/ssm/ssmfakes"
 )
 
-func testAWSClientFactory() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI) {
-	return &s3fakes.FakeS3API{}, &stsfakes.FakeSTSAPI{}, &ssmfakes.FakeSSMAPI{}, &secretsmanagerfakes.FakeSecretsManagerAPI{}
+func testAWSClientFactory() (s3.S3API, sts.STSAPI, ssm.SSMAPI, secretsmanager.SecretsManagerAPI, docker.ECRAPI) {
+	return &s3fakes.FakeS3API{}, &stsfakes.FakeSTSAPI{}, &ssmfakes.FakeSSMAPI{}, &secretsmanagerfakes.FakeSecretsManagerAPI{}, &dockerfakes.FakeECRAPI{}
 }
 
 func TestCLI(t *testing.T) {
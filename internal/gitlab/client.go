@@ -0,0 +1,189 @@
+// Package gitlab implements a minimal client for managing CI/CD variables through the GitLab API
+// (https://docs.gitlab.com/ee/api/project_level_variables.html and https://docs.gitlab.com/ee/api/group_level_variables.html).
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultAddress is the address of gitlab.com.
+const DefaultAddress = "https://gitlab.com"
+
+// Variable is a CI/CD variable.
+type Variable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type,omitempty"`
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	Raw              bool   `json:"raw"`
+	EnvironmentScope string `json:"environment_scope,omitempty"`
+}
+
+// ResponseError is returned when the API responds with an unexpected status code.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a ResponseError with status code 404.
+func IsNotFound(err error) bool {
+	var e *ResponseError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// Project returns the resource for a project, identified by ID or path (e.g. "group/project").
+func Project(id string) string {
+	return "projects/" + url.PathEscape(id)
+}
+
+// Group returns the resource for a group, identified by ID or path (e.g. "group/subgroup").
+func Group(id string) string {
+	return "groups/" + url.PathEscape(id)
+}
+
+// NewClient returns a new client for the given GitLab address (e.g. https://gitlab.com) and access token.
+func NewClient(address, token string, options ...option) *Client {
+	c := &Client{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	if c.address == "" {
+		c.address = DefaultAddress
+	}
+	for _, optionFunc := range options {
+		optionFunc(c)
+	}
+	return c
+}
+
+type option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Client for the GitLab API.
+type Client struct {
+	address    string
+	token      string
+	httpClient *http.Client
+}
+
+// GetVariable returns the variable with the given key and environment scope for a resource (see Project and Group).
+// Returns nil (and no error) if the variable does not exist.
+func (c *Client) GetVariable(ctx context.Context, resource, key, environmentScope string) (*Variable, error) {
+	var v Variable
+	if err := c.do(ctx, http.MethodGet, variablePath(resource, key, environmentScope), nil, &v); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CreateVariable creates a new variable for a resource.
+func (c *Client) CreateVariable(ctx context.Context, resource string, variable *Variable) (*Variable, error) {
+	var v Variable
+	if err := c.do(ctx, http.MethodPost, resource+"/variables", variable, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// UpdateVariable updates an existing variable for a resource.
+func (c *Client) UpdateVariable(ctx context.Context, resource string, variable *Variable) (*Variable, error) {
+	var v Variable
+	if err := c.do(ctx, http.MethodPut, variablePath(resource, variable.Key, variable.EnvironmentScope), variable, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// DeleteVariable deletes the variable with the given key and environment scope for a resource.
+func (c *Client) DeleteVariable(ctx context.Context, resource, key, environmentScope string) error {
+	return c.do(ctx, http.MethodDelete, variablePath(resource, key, environmentScope), nil, nil)
+}
+
+// variablePath returns the path for a variable. The environment scope is used as a filter, since
+// the same key can be used for multiple scopes.
+func variablePath(resource, key, environmentScope string) string {
+	p := resource + "/variables/" + url.PathEscape(key)
+	if environmentScope != "" {
+		p += "?" + url.Values{"filter[environment_scope]": {environmentScope}}.Encode()
+	}
+	return p
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal request: %s", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/api/v4/"+path, body)
+	if err != nil {
+		return fmt.Errorf("new request: %s", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e) // The body is not guaranteed to be JSON.
+		message := e.Error
+		if e.Message != nil {
+			// The message is either a string or an object with validation errors.
+			if m, ok := e.Message.(string); ok {
+				message = m
+			} else if b, err := json.Marshal(e.Message); err == nil {
+				message = string(b)
+			}
+		}
+		return &ResponseError{StatusCode: resp.StatusCode, Message: message}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred/internal/gitlab"
+)
+
+func TestClient(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.RequestURI)
+		assert.Equal(t, "token", r.Header.Get("PRIVATE-TOKEN"))
+
+		switch r.Method + " " + r.RequestURI {
+		case "GET /api/v4/projects/group%2Fproject/variables/KEY?filter%5Benvironment_scope%5D=production":
+			w.Write([]byte(`{"key":"KEY","value":"value","masked":true,"environment_scope":"production"}`)) //nolint:errcheck
+		case "GET /api/v4/projects/group%2Fproject/variables/MISSING?filter%5Benvironment_scope%5D=%2A":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"404 Variable Not Found"}`)) //nolint:errcheck
+		case "POST /api/v4/groups/group/variables":
+			var v gitlab.Variable
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&v))
+			assert.Equal(t, gitlab.Variable{Key: "KEY", Value: "value", Masked: true, Raw: true, EnvironmentScope: "*"}, v)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(v) //nolint:errcheck
+		case "DELETE /api/v4/groups/group/variables/KEY?filter%5Benvironment_scope%5D=%2A":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":{"value":["is invalid"]}}`)) //nolint:errcheck
+		}
+	}))
+	defer server.Close()
+
+	client := gitlab.NewClient(server.URL, "token")
+
+	v, err := client.GetVariable(context.TODO(), gitlab.Project("group/project"), "KEY", "production")
+	require.NoError(t, err)
+	assert.Equal(t, &gitlab.Variable{Key: "KEY", Value: "value", Masked: true, EnvironmentScope: "production"}, v)
+
+	v, err = client.GetVariable(context.TODO(), gitlab.Project("group/project"), "MISSING", "*")
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	_, err = client.CreateVariable(context.TODO(), gitlab.Group("group"), &gitlab.Variable{Key: "KEY", Value: "value", Masked: true, Raw: true, EnvironmentScope: "*"})
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeleteVariable(context.TODO(), gitlab.Group("group"), "KEY", "*"))
+
+	_, err = client.UpdateVariable(context.TODO(), gitlab.Project("group/project"), &gitlab.Variable{Key: "KEY", Value: "short"})
+	assert.EqualError(t, err, `unexpected status code: 400: {"value":["is invalid"]}`)
+
+	assert.Equal(t, []string{
+		"GET /api/v4/projects/group%2Fproject/variables/KEY?filter%5Benvironment_scope%5D=production",
+		"GET /api/v4/projects/group%2Fproject/variables/MISSING?filter%5Benvironment_scope%5D=%2A",
+		"POST /api/v4/groups/group/variables",
+		"DELETE /api/v4/groups/group/variables/KEY?filter%5Benvironment_scope%5D=%2A",
+		"PUT /api/v4/projects/group%2Fproject/variables/KEY",
+	}, requests)
+}
@@ -0,0 +1,249 @@
+// Package kubernetes implements a minimal client for managing Secrets through the Kubernetes API
+// (https://kubernetes.io/docs/reference/kubernetes-api/config-and-storage-resources/secret-v1/).
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Paths used when running inside a cluster.
+const (
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCAPath    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// Secret is a Kubernetes Secret. Data is base64 encoded by encoding/json, which is the format used by the API.
+type Secret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   ObjectMeta        `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	Data       map[string][]byte `json:"data,omitempty"`
+}
+
+// ObjectMeta is the metadata of a Kubernetes object.
+type ObjectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+// SecretPatch is a JSON merge patch (RFC 7386) for a Secret, which only changes the fields that are set. Keys in
+// data with a nil value are removed. When a resource version is set, the patch fails with a conflict if it is stale.
+type SecretPatch struct {
+	Metadata PatchMeta         `json:"metadata"`
+	Data     map[string][]byte `json:"data,omitempty"`
+}
+
+// PatchMeta is the metadata of a SecretPatch.
+type PatchMeta struct {
+	Labels          map[string]string `json:"labels,omitempty"`
+	Annotations     map[string]string `json:"annotations,omitempty"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+// StatusError is returned when the API responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is a StatusError with status code 404.
+func IsNotFound(err error) bool {
+	var e *StatusError
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// NewClient returns a new client for the given API server address (e.g. https://kubernetes.default.svc) and bearer token.
+func NewClient(address, token string, options ...option) *Client {
+	c := &Client{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, optionFunc := range options {
+		optionFunc(c)
+	}
+	return c
+}
+
+// NewInClusterClient returns a new client using the service account of the pod.
+func NewInClusterClient() (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a cluster (KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT must be set)")
+	}
+	token, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("read service account token: %s", err)
+	}
+	ca, err := os.ReadFile(serviceAccountCAPath)
+	if err != nil {
+		return nil, fmt.Errorf("read service account ca: %s", err)
+	}
+	httpClient, err := httpClientWithCA(ca)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient("https://"+net.JoinHostPort(host, port), strings.TrimSpace(string(token)), WithHTTPClient(httpClient)), nil
+}
+
+type option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithCACertificate sets the (PEM encoded) certificate authority used to verify the API server.
+// The system roots are used if the certificate is empty.
+func WithCACertificate(ca []byte) option {
+	return func(c *Client) {
+		if len(ca) == 0 {
+			return
+		}
+		httpClient, err := httpClientWithCA(ca)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.httpClient = httpClient
+	}
+}
+
+func httpClientWithCA(ca []byte) (*http.Client, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("no certificates found in ca")
+	}
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		},
+	}, nil
+}
+
+// Client for the Kubernetes API.
+type Client struct {
+	address    string
+	token      string
+	httpClient *http.Client
+	err        error
+}
+
+// GetSecret returns the secret with the given name. Returns nil (and no error) if the secret does not exist.
+func (c *Client) GetSecret(ctx context.Context, namespace, name string) (*Secret, error) {
+	var secret Secret
+	if err := c.do(ctx, http.MethodGet, secretPath(namespace, name), nil, &secret); err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// CreateSecret creates a new secret.
+func (c *Client) CreateSecret(ctx context.Context, secret *Secret) (*Secret, error) {
+	var out Secret
+	if err := c.do(ctx, http.MethodPost, secretPath(secret.Metadata.Namespace, ""), secret, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PatchSecret updates an existing secret using a JSON merge patch, which leaves all other fields of the secret untouched.
+func (c *Client) PatchSecret(ctx context.Context, namespace, name string, patch *SecretPatch) (*Secret, error) {
+	var out Secret
+	if err := c.do(ctx, http.MethodPatch, secretPath(namespace, name), patch, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteSecret deletes the secret with the given name.
+func (c *Client) DeleteSecret(ctx context.Context, namespace, name string) error {
+	return c.do(ctx, http.MethodDelete, secretPath(namespace, name), nil, nil)
+}
+
+func secretPath(namespace, name string) string {
+	p := "/api/v1/namespaces/" + namespace + "/secrets"
+	if name != "" {
+		p += "/" + name
+	}
+	return p
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("marshal request: %s", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.address+path, body)
+	if err != nil {
+		return fmt.Errorf("new request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		contentType := "application/json"
+		if method == http.MethodPatch {
+			contentType = "application/merge-patch+json"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var status struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&status) // The body is not guaranteed to be JSON.
+		return &StatusError{StatusCode: resp.StatusCode, Message: status.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %s", err)
+	}
+	return nil
+}
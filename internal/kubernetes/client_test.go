@@ -0,0 +1,97 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred/internal/kubernetes"
+)
+
+func TestClient(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/namespaces/default/secrets/existing":
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"existing","namespace":"default","resourceVersion":"1"},"data":{"value":"c2VjcmV0"}}`)) //nolint:errcheck
+		case "POST /api/v1/namespaces/default/secrets":
+			var secret kubernetes.Secret
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&secret))
+			assert.Equal(t, "created", secret.Metadata.Name)
+			assert.Equal(t, map[string][]byte{"value": []byte("secret")}, secret.Data)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(secret) //nolint:errcheck
+		case "PATCH /api/v1/namespaces/default/secrets/existing":
+			assert.Equal(t, "application/merge-patch+json", r.Header.Get("Content-Type"))
+			var patch map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			assert.Equal(t, map[string]interface{}{
+				"metadata": map[string]interface{}{"resourceVersion": "1"},
+				"data":     map[string]interface{}{"value": nil, "other": "c2VjcmV0"},
+			}, patch)
+			w.Write([]byte(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"existing","namespace":"default","resourceVersion":"2"},"data":{"other":"c2VjcmV0"}}`)) //nolint:errcheck
+		case "DELETE /api/v1/namespaces/default/secrets/existing":
+			w.Write([]byte(`{"kind":"Status","status":"Success"}`)) //nolint:errcheck
+		case "GET /api/v1/namespaces/default/secrets/missing", "DELETE /api/v1/namespaces/default/secrets/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","message":"secrets \"missing\" not found"}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","message":"forbidden"}`)) //nolint:errcheck
+		}
+	}))
+	defer server.Close()
+
+	client := kubernetes.NewClient(server.URL, "token")
+
+	secret, err := client.GetSecret(context.TODO(), "default", "existing")
+	require.NoError(t, err)
+	assert.Equal(t, "1", secret.Metadata.ResourceVersion)
+	assert.Equal(t, []byte("secret"), secret.Data["value"])
+
+	secret, err = client.GetSecret(context.TODO(), "default", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, secret)
+
+	_, err = client.CreateSecret(context.TODO(), &kubernetes.Secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   kubernetes.ObjectMeta{Name: "created", Namespace: "default"},
+		Data:       map[string][]byte{"value": []byte("secret")},
+	})
+	require.NoError(t, err)
+
+	secret, err = client.PatchSecret(context.TODO(), "default", "existing", &kubernetes.SecretPatch{
+		Metadata: kubernetes.PatchMeta{ResourceVersion: "1"},
+		Data:     map[string][]byte{"value": nil, "other": []byte("secret")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2", secret.Metadata.ResourceVersion)
+
+	require.NoError(t, client.DeleteSecret(context.TODO(), "default", "existing"))
+
+	_, err = client.PatchSecret(context.TODO(), "default", "forbidden", &kubernetes.SecretPatch{})
+	assert.EqualError(t, err, "unexpected status code: 403: forbidden")
+
+	err = client.DeleteSecret(context.TODO(), "default", "missing")
+	assert.True(t, kubernetes.IsNotFound(err))
+
+	assert.Equal(t, []string{
+		"GET /api/v1/namespaces/default/secrets/existing",
+		"GET /api/v1/namespaces/default/secrets/missing",
+		"POST /api/v1/namespaces/default/secrets",
+		"PATCH /api/v1/namespaces/default/secrets/existing",
+		"DELETE /api/v1/namespaces/default/secrets/existing",
+		"PATCH /api/v1/namespaces/default/secrets/forbidden",
+		"DELETE /api/v1/namespaces/default/secrets/missing",
+	}, requests)
+}
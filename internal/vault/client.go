@@ -0,0 +1,154 @@
+// Package vault implements a minimal client for the HashiCorp Vault HTTP API (https://www.vaultproject.io/api-docs).
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Secret is the response from Vault when reading or writing secrets.
+type Secret struct {
+	RequestID     string                 `json:"request_id"`
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int                    `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// ResponseError is returned when Vault responds with an unexpected status code.
+type ResponseError struct {
+	StatusCode int
+	Errors     []string
+}
+
+// Error implements error.
+func (e *ResponseError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, strings.Join(e.Errors, ", "))
+}
+
+// IsNotFound returns true if the error is a ResponseError with status code 404.
+func IsNotFound(err error) bool {
+	e, ok := err.(*ResponseError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// NewClient returns a new client for the given Vault address (e.g. https://vault.example.com:8200) and token.
+func NewClient(address, token string, options ...option) *Client {
+	c := &Client{
+		address:    strings.TrimSuffix(address, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, optionFunc := range options {
+		optionFunc(c)
+	}
+	return c
+}
+
+type option func(*Client)
+
+// WithNamespace sets the (Vault Enterprise) namespace used for requests.
+func WithNamespace(namespace string) option {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(client *http.Client) option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Client for the Vault HTTP API.
+type Client struct {
+	address    string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// Read the secret at the given path. Returns nil (and no error) if the secret does not exist.
+func (c *Client) Read(ctx context.Context, path string) (*Secret, error) {
+	secret, err := c.do(ctx, http.MethodGet, path, nil)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// Write data to the given path. Returns the secret in the response (if any).
+func (c *Client) Write(ctx context.Context, path string, data map[string]interface{}) (*Secret, error) {
+	return c.do(ctx, http.MethodPut, path, data)
+}
+
+// Delete the secret at the given path.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.do(ctx, http.MethodDelete, path, nil)
+	return err
+}
+
+// RevokeLease revokes the lease with the given ID.
+func (c *Client) RevokeLease(ctx context.Context, leaseID string) error {
+	_, err := c.do(ctx, http.MethodPut, "sys/leases/revoke", map[string]interface{}{"lease_id": leaseID})
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, data map[string]interface{}) (*Secret, error) {
+	var body io.Reader
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %s", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/v1/"+strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %s", err)
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	req.Header.Set("X-Vault-Request", "true")
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e) // The body is not guaranteed to be JSON.
+		return nil, &ResponseError{StatusCode: resp.StatusCode, Errors: e.Errors}
+	}
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var secret Secret
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("decode response: %s", err)
+	}
+	return &secret, nil
+}
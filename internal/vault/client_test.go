@@ -0,0 +1,65 @@
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred/internal/vault"
+)
+
+func TestClient(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "team", r.Header.Get("X-Vault-Namespace"))
+
+		switch r.URL.Path {
+		case "/v1/database/creds/readonly":
+			w.Write([]byte(`{"lease_id":"database/creds/readonly/abc","lease_duration":3600,"data":{"username":"user","password":"pass"}}`)) //nolint:errcheck
+		case "/v1/sys/leases/revoke":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "database/creds/readonly/abc", body["lease_id"])
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/secret/data/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`)) //nolint:errcheck
+		}
+	}))
+	defer server.Close()
+
+	client := vault.NewClient(server.URL, "token", vault.WithNamespace("team"))
+
+	secret, err := client.Read(context.TODO(), "database/creds/readonly")
+	require.NoError(t, err)
+	assert.Equal(t, "database/creds/readonly/abc", secret.LeaseID)
+	assert.Equal(t, 3600, secret.LeaseDuration)
+	assert.Equal(t, map[string]interface{}{"username": "user", "password": "pass"}, secret.Data)
+
+	require.NoError(t, client.RevokeLease(context.TODO(), secret.LeaseID))
+
+	secret, err = client.Read(context.TODO(), "secret/data/missing")
+	require.NoError(t, err)
+	assert.Nil(t, secret)
+
+	_, err = client.Write(context.TODO(), "secret/data/forbidden", map[string]interface{}{"key": "value"})
+	assert.EqualError(t, err, "unexpected status code: 403: permission denied")
+
+	assert.Equal(t, []string{
+		"GET /v1/database/creds/readonly",
+		"PUT /v1/sys/leases/revoke",
+		"GET /v1/secret/data/missing",
+		"PUT /v1/secret/data/forbidden",
+	}, requests)
+}
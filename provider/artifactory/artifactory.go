@@ -23,23 +23,37 @@ package artifactory
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/artifactory/services"
 	"github.com/jfrog/jfrog-client-go/utils/log"
+	"go.uber.org/zap"
 
 	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/eventctx"
 )
 
-var _ sidecred.Validatable = &RequestConfig{}
+var (
+	_ sidecred.Validatable      = &RequestConfig{}
+	_ sidecred.RequestValidator = &provider{}
+)
 
 // RequestConfig ...
 // The generated secrets will be `<name>-artifactory-user` and
-// `<name>-artifactory-token`.
+// `<name>-artifactory-token` (and `<name>-artifactory-refresh-token` for
+// refreshable tokens).
+//
+// By default, the token is scoped to `api:* member-of-groups:<groups>`, where
+// groups is a comma separated list of the requested groups. Alternatively,
+// scope can be used to specify the full scope of the token, in which case
+// groups must not be specified.
 //
 // The following shows an example resource configuration as YAML (note that the
 // lambda version expects JSON):
@@ -48,7 +62,9 @@ var _ sidecred.Validatable = &RequestConfig{}
 //		  name: my-writer
 //		  config:
 //		    user: concourse-artifactory-user
-//		    group: artifactory-writers-group
+//		    groups:
+//		      - artifactory-readers-group
+//		      - artifactory-writers-group
 //		    duration: 30m
 //
 // For this specific example, the provider will create the secrets
@@ -64,6 +80,18 @@ type RequestConfig struct {
 	// The user will inherit the group permissions.
 	Group string `json:"group"`
 
+	// Groups to associate the credentials with (in addition to group).
+	Groups []string `json:"groups,omitempty"`
+
+	// Scope overrides the default scope of the token.
+	Scope string `json:"scope,omitempty"`
+
+	// Audience restricts the services that accept the token, e.g. `jfrt@*`.
+	Audience string `json:"audience,omitempty"`
+
+	// Refreshable tokens also return a refresh token.
+	Refreshable bool `json:"refreshable,omitempty"`
+
 	// Request-specific override for credential duration.
 	Duration *sidecred.Duration `json:"duration"`
 }
@@ -73,12 +101,41 @@ func (c *RequestConfig) Validate() error {
 	if c.User == "" {
 		return fmt.Errorf("%q must be defined", "user")
 	}
-	if c.Group == "" {
-		return fmt.Errorf("%q must be defined", "group")
+	groups := c.groups()
+	if c.Scope == "" && len(groups) == 0 {
+		return fmt.Errorf("%q, %q or %q must be defined", "group", "groups", "scope")
+	}
+	if c.Scope != "" && len(groups) > 0 {
+		return fmt.Errorf("%q cannot be combined with %q or %q", "scope", "group", "groups")
+	}
+	for _, g := range groups {
+		if g == "" || strings.ContainsAny(g, ", ") {
+			return fmt.Errorf("invalid group: %q", g)
+		}
+	}
+	if c.Duration != nil && c.Duration.Duration <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "duration")
 	}
 	return nil
 }
 
+// groups returns the union of group and groups.
+func (c *RequestConfig) groups() []string {
+	var groups []string
+	if c.Group != "" {
+		groups = append(groups, c.Group)
+	}
+	return append(groups, c.Groups...)
+}
+
+// scope returns the scope for the token.
+func (c *RequestConfig) scope() string {
+	if c.Scope != "" {
+		return c.Scope
+	}
+	return fmt.Sprintf("api:* member-of-groups:%s", strings.Join(c.groups(), ","))
+}
+
 // NewClient returns a new client for ArtifactoryAPI.
 func NewClient(hostname, username, password, accessToken, apiKey string) (ArtifactoryAPI, error) {
 	rtDetails := auth.NewArtifactoryDetails()
@@ -121,9 +178,19 @@ func WithSessionDuration(duration time.Duration) option {
 	}
 }
 
+// WithMaxSessionDuration sets the maximum duration for tokens (requested or
+// default), which should match the limit configured for the Artifactory server.
+// Zero (the default) means that requests are not limited.
+func WithMaxSessionDuration(duration time.Duration) option {
+	return func(p *provider) {
+		p.maxSessionDuration = duration
+	}
+}
+
 type provider struct {
-	client          ArtifactoryAPI
-	sessionDuration time.Duration
+	client             ArtifactoryAPI
+	sessionDuration    time.Duration
+	maxSessionDuration time.Duration
 }
 
 // Type implements sidecred.Provider.
@@ -131,33 +198,82 @@ func (p *provider) Type() sidecred.ProviderType {
 	return sidecred.Artifactory
 }
 
-// Create implements sidecred.Provider.
-func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+// ValidateRequest implements sidecred.RequestValidator.
+func (p *provider) ValidateRequest(_ context.Context, request *sidecred.CredentialRequest) error {
+	if _, err := p.parseConfig(request); err != nil {
+		return &sidecred.InvalidRequestError{Err: err}
+	}
+	return nil
+}
+
+func (p *provider) parseConfig(request *sidecred.CredentialRequest) (*RequestConfig, error) {
 	var c RequestConfig
 	if err := request.UnmarshalConfig(&c); err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err)
 	}
-	duration := int(p.sessionDuration.Seconds())
+	// Validate the effective duration, since the default session duration can also exceed the maximum.
+	if duration := p.duration(&c); p.maxSessionDuration > 0 && duration > p.maxSessionDuration {
+		source := "requested"
+		if c.Duration == nil {
+			source = "default"
+		}
+		return nil, fmt.Errorf("%s duration (%s) exceeds the maximum (%s)", source, duration, p.maxSessionDuration)
+	}
+	return &c, nil
+}
+
+// duration returns the requested duration, or the default session duration if none was requested.
+func (p *provider) duration(c *RequestConfig) time.Duration {
 	if c.Duration != nil {
-		duration = int(c.Duration.Seconds())
+		return c.Duration.Duration
 	}
+	return p.sessionDuration
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	c, err := p.parseConfig(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	duration := int(p.duration(c).Seconds())
 
 	params := services.CreateTokenParams{
-		Scope:       fmt.Sprintf("api:* member-of-groups:%s", c.Group),
+		Scope:       c.scope(),
 		Username:    c.User,
 		ExpiresIn:   duration,
-		Refreshable: false,
-		Audience:    "",
+		Refreshable: c.Refreshable,
+		Audience:    c.Audience,
 	}
 
-	expiry := time.Now().UTC().Add(time.Duration(duration) * time.Second)
+	// Use the time before the request when computing the expiration, so that
+	// we err on the side of rotating the token early.
+	issuedAt := time.Now().UTC()
 
 	output, err := p.client.CreateToken(params)
 	if err != nil {
 		return nil, nil, fmt.Errorf("create token: %s", err)
 	}
 
-	return []*sidecred.Credential{
+	// The server may cap the lifetime of the token, so we use the expiration
+	// from the response and fall back to the requested duration if missing.
+	expiresIn := duration
+	if output.ExpiresIn > 0 {
+		expiresIn = output.ExpiresIn
+	}
+	if expiresIn < duration {
+		eventctx.GetLogger(ctx).Warn("artifactory granted a shorter token lifetime than requested",
+			zap.String("name", request.Name),
+			zap.Duration("requested", time.Duration(duration)*time.Second),
+			zap.Duration("granted", time.Duration(expiresIn)*time.Second),
+		)
+	}
+	expiry := issuedAt.Add(time.Duration(expiresIn) * time.Second)
+
+	credentials := []*sidecred.Credential{
 		{
 			Name:        request.Name + "-artifactory-user",
 			Value:       c.User,
@@ -170,16 +286,83 @@ func (p *provider) Create(ctx context.Context, request *sidecred.CredentialReque
 			Expiration:  expiry,
 			Description: "Artifactory credentials managed by sidecred.",
 		},
-	}, nil, nil
+	}
+	if output.RefreshToken != "" {
+		credentials = append(credentials, &sidecred.Credential{
+			Name:        request.Name + "-artifactory-refresh-token",
+			Value:       output.RefreshToken,
+			Expiration:  expiry,
+			Description: "Artifactory credentials managed by sidecred.",
+		})
+	}
+
+	// The token ID is needed to revoke the token when it is rotated. Tokens
+	// that are not JWTs (i.e. without an ID) are left to expire on their own.
+	var metadata *sidecred.Metadata
+	if id := tokenID(output.AccessToken); id != "" {
+		metadata = &sidecred.Metadata{"token_id": id}
+	}
+	return credentials, metadata, nil
+}
+
+// tokenID returns the ID (jti claim) of an access token, or an empty string
+// if the token is not a JWT.
+func tokenID(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		ID string `json:"jti"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	return claims.ID
 }
 
 // Destroy implements sidecred.Provider.
-func (p *provider) Destroy(_ context.Context, _ *sidecred.Resource) error {
+func (p *provider) Destroy(_ context.Context, resource *sidecred.Resource) error {
+	if resource.Metadata == nil {
+		return nil
+	}
+	id := (*resource.Metadata)["token_id"]
+	if id == "" {
+		return nil
+	}
+	if _, err := p.client.RevokeToken(services.RevokeTokenParams{TokenId: id}); err != nil {
+		// Ignore the error if the token no longer exists (e.g. it has expired). The client does not
+		// expose the status code of the response, so we check whether the token is still listed.
+		if exists, listErr := p.tokenExists(id); listErr == nil && !exists {
+			return nil
+		}
+		return fmt.Errorf("revoke token: %s", err)
+	}
 	return nil
 }
 
+// tokenExists returns true if a token with the given ID is listed by Artifactory.
+func (p *provider) tokenExists(id string) (bool, error) {
+	tokens, err := p.client.GetTokens()
+	if err != nil {
+		return false, err
+	}
+	for _, t := range tokens.Tokens {
+		if t.TokenId == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ArtifactoryAPI wraps the Artifactory access token API.
 //counterfeiter:generate . ArtifactoryAPI
 type ArtifactoryAPI interface {
 	CreateToken(services.CreateTokenParams) (services.CreateTokenResponseData, error)
+	GetTokens() (services.GetTokensResponseData, error)
+	RevokeToken(services.RevokeTokenParams) (string, error)
 }
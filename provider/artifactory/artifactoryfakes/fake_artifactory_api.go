@@ -22,6 +22,31 @@ type FakeArtifactoryAPI struct {
 		result1 services.CreateTokenResponseData
 		result2 error
 	}
+	GetTokensStub        func() (services.GetTokensResponseData, error)
+	getTokensMutex       sync.RWMutex
+	getTokensArgsForCall []struct {
+	}
+	getTokensReturns struct {
+		result1 services.GetTokensResponseData
+		result2 error
+	}
+	getTokensReturnsOnCall map[int]struct {
+		result1 services.GetTokensResponseData
+		result2 error
+	}
+	RevokeTokenStub        func(services.RevokeTokenParams) (string, error)
+	revokeTokenMutex       sync.RWMutex
+	revokeTokenArgsForCall []struct {
+		arg1 services.RevokeTokenParams
+	}
+	revokeTokenReturns struct {
+		result1 string
+		result2 error
+	}
+	revokeTokenReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +115,135 @@ func (fake *FakeArtifactoryAPI) CreateTokenReturnsOnCall(i int, result1 services
 	}{result1, result2}
 }
 
+func (fake *FakeArtifactoryAPI) GetTokens() (services.GetTokensResponseData, error) {
+	fake.getTokensMutex.Lock()
+	ret, specificReturn := fake.getTokensReturnsOnCall[len(fake.getTokensArgsForCall)]
+	fake.getTokensArgsForCall = append(fake.getTokensArgsForCall, struct {
+	}{})
+	stub := fake.GetTokensStub
+	fakeReturns := fake.getTokensReturns
+	fake.recordInvocation("GetTokens", []interface{}{})
+	fake.getTokensMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArtifactoryAPI) GetTokensCallCount() int {
+	fake.getTokensMutex.RLock()
+	defer fake.getTokensMutex.RUnlock()
+	return len(fake.getTokensArgsForCall)
+}
+
+func (fake *FakeArtifactoryAPI) GetTokensCalls(stub func() (services.GetTokensResponseData, error)) {
+	fake.getTokensMutex.Lock()
+	defer fake.getTokensMutex.Unlock()
+	fake.GetTokensStub = stub
+}
+
+func (fake *FakeArtifactoryAPI) GetTokensReturns(result1 services.GetTokensResponseData, result2 error) {
+	fake.getTokensMutex.Lock()
+	defer fake.getTokensMutex.Unlock()
+	fake.GetTokensStub = nil
+	fake.getTokensReturns = struct {
+		result1 services.GetTokensResponseData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArtifactoryAPI) GetTokensReturnsOnCall(i int, result1 services.GetTokensResponseData, result2 error) {
+	fake.getTokensMutex.Lock()
+	defer fake.getTokensMutex.Unlock()
+	fake.GetTokensStub = nil
+	if fake.getTokensReturnsOnCall == nil {
+		fake.getTokensReturnsOnCall = make(map[int]struct {
+			result1 services.GetTokensResponseData
+			result2 error
+		})
+	}
+	fake.getTokensReturnsOnCall[i] = struct {
+		result1 services.GetTokensResponseData
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArtifactoryAPI) RevokeToken(arg1 services.RevokeTokenParams) (string, error) {
+	fake.revokeTokenMutex.Lock()
+	ret, specificReturn := fake.revokeTokenReturnsOnCall[len(fake.revokeTokenArgsForCall)]
+	fake.revokeTokenArgsForCall = append(fake.revokeTokenArgsForCall, struct {
+		arg1 services.RevokeTokenParams
+	}{arg1})
+	stub := fake.RevokeTokenStub
+	fakeReturns := fake.revokeTokenReturns
+	fake.recordInvocation("RevokeToken", []interface{}{arg1})
+	fake.revokeTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArtifactoryAPI) RevokeTokenCallCount() int {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	return len(fake.revokeTokenArgsForCall)
+}
+
+func (fake *FakeArtifactoryAPI) RevokeTokenCalls(stub func(services.RevokeTokenParams) (string, error)) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = stub
+}
+
+func (fake *FakeArtifactoryAPI) RevokeTokenArgsForCall(i int) services.RevokeTokenParams {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	argsForCall := fake.revokeTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeArtifactoryAPI) RevokeTokenReturns(result1 string, result2 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	fake.revokeTokenReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArtifactoryAPI) RevokeTokenReturnsOnCall(i int, result1 string, result2 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	if fake.revokeTokenReturnsOnCall == nil {
+		fake.revokeTokenReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.revokeTokenReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeArtifactoryAPI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.createTokenMutex.RLock()
 	defer fake.createTokenMutex.RUnlock()
+	fake.getTokensMutex.RLock()
+	defer fake.getTokensMutex.RUnlock()
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,215 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	provider "github.com/telia-oss/sidecred/provider/database"
+	"github.com/telia-oss/sidecred/provider/database/databasefakes"
+)
+
+func TestDatabaseProvider(t *testing.T) {
+	tests := []struct {
+		description      string
+		dialect          string
+		config           string
+		execError        error
+		expectedUsername string
+		expectedQueries  []string
+		expectedMetadata bool
+		expectedDuration time.Duration
+		expectedError    string
+	}{
+		{
+			description:      "alters postgres roles",
+			dialect:          provider.Postgres,
+			config:           `{"connection":"main","role":"app"}`,
+			expectedUsername: "^app$",
+			expectedQueries: []string{
+				`^ALTER ROLE "app" WITH PASSWORD '[a-zA-Z0-9]{32}'$`,
+			},
+			expectedDuration: 24 * time.Hour,
+		},
+		{
+			description:      "alters mysql users",
+			dialect:          provider.MySQL,
+			config:           `{"connection":"main","role":"app","host":"10.0.0.%","password_length":16,"rotation_interval":"1h"}`,
+			expectedUsername: "^app$",
+			expectedQueries: []string{
+				`^ALTER USER 'app'@'10\.0\.0\.%' IDENTIFIED BY '[a-zA-Z0-9]{16}'$`,
+			},
+			expectedDuration: time.Hour,
+		},
+		{
+			description:      "creates temporary postgres roles for rollover",
+			dialect:          provider.Postgres,
+			config:           `{"connection":"main","role":"app","rollover":true}`,
+			expectedUsername: "^app_[a-f0-9]{8}$",
+			expectedQueries: []string{
+				`^CREATE ROLE "app_[a-f0-9]{8}" WITH LOGIN PASSWORD '[a-zA-Z0-9]{32}' IN ROLE "app"$`,
+			},
+			expectedMetadata: true,
+			expectedDuration: 24 * time.Hour,
+		},
+		{
+			description:      "creates temporary mysql users for rollover",
+			dialect:          provider.MySQL,
+			config:           `{"connection":"main","role":"app","rollover":true}`,
+			expectedUsername: "^app_[a-f0-9]{8}$",
+			expectedQueries: []string{
+				`^CREATE USER 'app_[a-f0-9]{8}'@'%' IDENTIFIED BY '[a-zA-Z0-9]{32}'$`,
+				`^GRANT 'app'@'%' TO 'app_[a-f0-9]{8}'@'%'$`,
+				`^SET DEFAULT ROLE ALL TO 'app_[a-f0-9]{8}'@'%'$`,
+			},
+			expectedMetadata: true,
+			expectedDuration: 24 * time.Hour,
+		},
+		{
+			description:   "fails for unknown connections",
+			dialect:       provider.Postgres,
+			config:        `{"connection":"other","role":"app"}`,
+			expectedError: `unknown connection "other"`,
+		},
+		{
+			description:   "fails for invalid roles",
+			dialect:       provider.Postgres,
+			config:        `{"connection":"main","role":"app\"; DROP TABLE users; --"}`,
+			expectedError: `invalid config: invalid "role": "app\"; DROP TABLE users; --"`,
+		},
+		{
+			description: "propagates errors",
+			dialect:     provider.Postgres,
+			config:      `{"connection":"main","role":"app"}`,
+			execError:   errors.New("role \"app\" does not exist"),
+			expectedQueries: []string{
+				`^ALTER ROLE "app" WITH PASSWORD '[a-zA-Z0-9]{32}'$`,
+			},
+			expectedError: `update role: role "app" does not exist`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeExecutor := &databasefakes.FakeExecutor{}
+			fakeExecutor.ExecContextReturns(nil, tc.execError)
+
+			p := provider.New(map[string]*provider.Connection{
+				"main": {Dialect: tc.dialect, Executor: fakeExecutor},
+			}, provider.WithRotationInterval(24*time.Hour))
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.DatabasePassword,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+
+			require.Equal(t, len(tc.expectedQueries), fakeExecutor.ExecContextCallCount(), "exec calls")
+			for i, expected := range tc.expectedQueries {
+				_, query, _ := fakeExecutor.ExecContextArgsForCall(i)
+				assert.Regexp(t, regexp.MustCompile(expected), query)
+			}
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, creds, 2)
+
+			assert.Equal(t, "request-name-username", creds[0].Name)
+			assert.Regexp(t, regexp.MustCompile(tc.expectedUsername), creds[0].Value)
+			assert.Equal(t, "request-name-password", creds[1].Name)
+			for _, c := range creds {
+				assert.WithinDuration(t, time.Now().Add(tc.expectedDuration), c.Expiration, time.Minute)
+			}
+
+			if tc.expectedMetadata {
+				require.NotNil(t, metadata)
+				assert.Equal(t, "main", (*metadata)["connection"])
+				assert.Equal(t, creds[0].Value, (*metadata)["role"])
+			} else {
+				assert.Nil(t, metadata)
+			}
+		})
+	}
+}
+
+func TestDatabaseProviderDestroy(t *testing.T) {
+	tests := []struct {
+		description     string
+		dialect         string
+		metadata        *sidecred.Metadata
+		execError       error
+		expectedQueries []string
+		expectedError   string
+	}{
+		{
+			description: "reassigns owned objects and drops temporary postgres roles",
+			dialect:     provider.Postgres,
+			metadata:    &sidecred.Metadata{"connection": "main", "role": "app_0a1b2c3d"},
+			expectedQueries: []string{
+				`DO 'BEGIN IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = ''app_0a1b2c3d'') THEN ` +
+					`REASSIGN OWNED BY "app_0a1b2c3d" TO "app"; DROP OWNED BY "app_0a1b2c3d"; END IF; END'`,
+				`DROP ROLE IF EXISTS "app_0a1b2c3d"`,
+			},
+		},
+		{
+			description:     "drops temporary mysql users",
+			dialect:         provider.MySQL,
+			metadata:        &sidecred.Metadata{"connection": "main", "role": "app_0a1b2c3d", "host": "localhost"},
+			expectedQueries: []string{`DROP USER IF EXISTS 'app_0a1b2c3d'@'localhost'`},
+		},
+		{
+			description: "does nothing without metadata",
+			dialect:     provider.Postgres,
+		},
+		{
+			description:     "propagates errors",
+			dialect:         provider.MySQL,
+			metadata:        &sidecred.Metadata{"connection": "main", "role": "app_0a1b2c3d"},
+			execError:       errors.New("failure"),
+			expectedQueries: []string{`DROP USER IF EXISTS 'app_0a1b2c3d'@'%'`},
+			expectedError:   "drop role: failure",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeExecutor := &databasefakes.FakeExecutor{}
+			fakeExecutor.ExecContextReturns(nil, tc.execError)
+
+			p := provider.New(map[string]*provider.Connection{
+				"main": {Dialect: tc.dialect, Executor: fakeExecutor},
+			})
+			resource := &sidecred.Resource{
+				Type:     sidecred.DatabasePassword,
+				ID:       "request-name",
+				Config:   []byte(`{"connection":"main","role":"app","rollover":true}`),
+				Metadata: tc.metadata,
+			}
+
+			// Temporary roles are kept until they expire after being rotated out.
+			deferrer, ok := p.(sidecred.DestroyDeferrer)
+			require.True(t, ok, "implements sidecred.DestroyDeferrer")
+			assert.Equal(t, tc.metadata != nil, deferrer.DeferDestroy(resource))
+
+			err := p.Destroy(context.TODO(), resource)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			require.Equal(t, len(tc.expectedQueries), fakeExecutor.ExecContextCallCount(), "exec calls")
+			for i, expected := range tc.expectedQueries {
+				_, query, _ := fakeExecutor.ExecContextArgsForCall(i)
+				assert.Equal(t, expected, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+// Package docker implements a sidecred.Provider for Docker registry credentials. The provider composes credentials
+// for one or more registries (e.g. Artifactory access tokens or ECR authorization tokens) into a single Docker
+// configuration file (.docker/config.json).
+package docker
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/provider/artifactory"
+)
+
+var _ sidecred.Validatable = &RequestConfig{}
+
+// RequestConfig ...
+// Each registry must specify exactly one source of credentials:
+//
+//   - artifactory: an Artifactory access token request (see artifactory.RequestConfig)
+//   - ecr: an ECR authorization token (the address defaults to the proxy endpoint of the registry)
+//   - static: the name of static credentials configured for the provider
+//
+// The provider writes a single credential (named after the request) containing the Docker configuration, and the
+// expiration is the earliest expiration of the registry credentials (static credentials are considered to expire
+// after the rotation interval).
+//
+// The following shows an example resource configuration as YAML:
+//
+//	creds:
+//	  - type: docker:config
+//	    name: docker-config
+//	    config:
+//	      registries:
+//	        - address: my-org.jfrog.io
+//	          artifactory:
+//	            user: concourse-artifactory-user
+//	            group: artifactory-readers-group
+//	        - ecr: {}
+//	        - address: https://index.docker.io/v1/
+//	          static: dockerhub
+type RequestConfig struct {
+	Registries []*Registry `json:"registries"`
+}
+
+// Registry in the Docker configuration.
+type Registry struct {
+	Address     string                     `json:"address,omitempty"`
+	Artifactory *artifactory.RequestConfig `json:"artifactory,omitempty"`
+	ECR         *ECRConfig                 `json:"ecr,omitempty"`
+	Static      string                     `json:"static,omitempty"`
+}
+
+// ECRConfig ...
+type ECRConfig struct {
+	// RegistryID is the AWS account ID of the registry, and defaults to the account of the credentials used by sidecred.
+	RegistryID string `json:"registry_id,omitempty"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *RequestConfig) Validate() error {
+	if len(c.Registries) == 0 {
+		return fmt.Errorf("%q must be defined", "registries")
+	}
+	addresses := make(map[string]struct{}, len(c.Registries))
+	for i, r := range c.Registries {
+		if r == nil {
+			return fmt.Errorf("registries[%d]: must be defined", i)
+		}
+		var sources int
+		if r.Artifactory != nil {
+			if err := r.Artifactory.Validate(); err != nil {
+				return fmt.Errorf("registries[%d]: artifactory: %s", i, err)
+			}
+			sources++
+		}
+		if r.ECR != nil {
+			sources++
+		}
+		if r.Static != "" {
+			sources++
+		}
+		if sources != 1 {
+			return fmt.Errorf("registries[%d]: exactly one of %q, %q or %q must be defined", i, "artifactory", "ecr", "static")
+		}
+		if r.Address == "" {
+			if r.ECR == nil {
+				return fmt.Errorf("registries[%d]: %q must be defined", i, "address")
+			}
+			continue
+		}
+		if _, found := addresses[r.Address]; found {
+			return fmt.Errorf("registries[%d]: duplicate address %q", i, r.Address)
+		}
+		addresses[r.Address] = struct{}{}
+	}
+	return nil
+}
+
+// Config is the Docker configuration file.
+type Config struct {
+	Auths map[string]Auth `json:"auths"`
+}
+
+// Auth for a registry in the Docker configuration file.
+type Auth struct {
+	Auth string `json:"auth"`
+}
+
+// NewECRClient returns a new client for ECRAPI.
+func NewECRClient(sess *session.Session) ECRAPI {
+	return ecr.New(sess)
+}
+
+// New returns a new sidecred.Provider for Docker configuration files.
+func New(options ...option) sidecred.Provider {
+	p := &provider{
+		staticCredentials: make(map[string]string),
+		rotationInterval:  time.Hour * 24 * 7,
+	}
+	for _, optionFunc := range options {
+		optionFunc(p)
+	}
+	return p
+}
+
+type option func(*provider)
+
+// WithArtifactoryProvider enables Artifactory access tokens as a source of credentials.
+func WithArtifactoryProvider(artifactoryProvider sidecred.Provider) option {
+	return func(p *provider) {
+		p.artifactory = artifactoryProvider
+	}
+}
+
+// WithECRClient enables ECR authorization tokens as a source of credentials.
+func WithECRClient(client ECRAPI) option {
+	return func(p *provider) {
+		p.ecr = client
+	}
+}
+
+// WithStaticCredentials sets static credentials (username:password) which can be referenced by name in requests.
+func WithStaticCredentials(credentials map[string]string) option {
+	return func(p *provider) {
+		for name, c := range credentials {
+			p.staticCredentials[name] = base64.StdEncoding.EncodeToString([]byte(c))
+		}
+	}
+}
+
+// WithRotationInterval sets the rotation interval for configurations that only use static credentials.
+func WithRotationInterval(duration time.Duration) option {
+	return func(p *provider) {
+		p.rotationInterval = duration
+	}
+}
+
+type provider struct {
+	artifactory       sidecred.Provider
+	ecr               ECRAPI
+	staticCredentials map[string]string
+	rotationInterval  time.Duration
+}
+
+// Type implements sidecred.Provider.
+func (p *provider) Type() sidecred.ProviderType {
+	return sidecred.Docker
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c RequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+
+	var (
+		config     = Config{Auths: make(map[string]Auth, len(c.Registries))}
+		metadata   = sidecred.Metadata{}
+		expiration = time.Now().Add(p.rotationInterval).UTC()
+	)
+	for i, r := range c.Registries {
+		address, auth, exp, err := p.createAuth(ctx, request.Name, r, metadata)
+		if err != nil {
+			// Clean up credentials that have already been created, since they will not be tracked in state.
+			p.destroyMetadata(ctx, request.Name, metadata) //nolint:errcheck
+			return nil, nil, fmt.Errorf("registries[%d]: %s", i, err)
+		}
+		if _, found := config.Auths[address]; found {
+			p.destroyMetadata(ctx, request.Name, metadata) //nolint:errcheck
+			return nil, nil, fmt.Errorf("registries[%d]: duplicate address %q", i, address)
+		}
+		config.Auths[address] = Auth{Auth: auth}
+		if !exp.IsZero() && exp.Before(expiration) {
+			expiration = exp
+		}
+	}
+
+	b, err := json.Marshal(config)
+	if err != nil {
+		p.destroyMetadata(ctx, request.Name, metadata) //nolint:errcheck
+		return nil, nil, fmt.Errorf("marshal docker config: %s", err)
+	}
+
+	var m *sidecred.Metadata
+	if len(metadata) > 0 {
+		m = &metadata
+	}
+	return []*sidecred.Credential{{
+		Name:        request.Name,
+		Value:       string(b),
+		Description: "Docker configuration managed by sidecred.",
+		Expiration:  expiration.UTC(),
+	}}, m, nil
+}
+
+// createAuth returns the address, auth and expiration for a registry. Metadata from the
+// underlying providers is added to metadata, so that the credentials can be destroyed.
+func (p *provider) createAuth(ctx context.Context, name string, r *Registry, metadata sidecred.Metadata) (string, string, time.Time, error) {
+	switch {
+	case r.Artifactory != nil:
+		if p.artifactory == nil {
+			return "", "", time.Time{}, fmt.Errorf("artifactory is not enabled")
+		}
+		config, err := json.Marshal(r.Artifactory)
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("marshal artifactory config: %s", err)
+		}
+		creds, m, err := p.artifactory.Create(ctx, &sidecred.CredentialRequest{
+			Type:   sidecred.ArtifactoryAccessToken,
+			Name:   name,
+			Config: config,
+		})
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("create artifactory token: %s", err)
+		}
+		if m != nil {
+			b, err := json.Marshal(m)
+			if err != nil {
+				return "", "", time.Time{}, fmt.Errorf("marshal artifactory metadata: %s", err)
+			}
+			metadata[artifactoryMetadataKey(r.Address)] = string(b)
+		}
+		var (
+			username, password string
+			expiration         time.Time
+		)
+		for _, c := range creds {
+			switch c.Name {
+			case name + "-artifactory-user":
+				username = c.Value
+			case name + "-artifactory-token":
+				password, expiration = c.Value, c.Expiration
+			}
+		}
+		if username == "" || password == "" {
+			return "", "", time.Time{}, fmt.Errorf("artifactory did not return a user and token")
+		}
+		return r.Address, encodeAuth(username, password), expiration, nil
+
+	case r.ECR != nil:
+		if p.ecr == nil {
+			return "", "", time.Time{}, fmt.Errorf("ecr is not enabled")
+		}
+		input := &ecr.GetAuthorizationTokenInput{}
+		if r.ECR.RegistryID != "" {
+			input.RegistryIds = []*string{aws.String(r.ECR.RegistryID)}
+		}
+		out, err := p.ecr.GetAuthorizationToken(input)
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("get ecr authorization token: %s", err)
+		}
+		if len(out.AuthorizationData) == 0 {
+			return "", "", time.Time{}, fmt.Errorf("no ecr authorization data returned")
+		}
+		data := out.AuthorizationData[0]
+		address := r.Address
+		if address == "" {
+			address = strings.TrimPrefix(aws.StringValue(data.ProxyEndpoint), "https://")
+		}
+		return address, aws.StringValue(data.AuthorizationToken), aws.TimeValue(data.ExpiresAt), nil
+
+	default:
+		auth, found := p.staticCredentials[r.Static]
+		if !found {
+			return "", "", time.Time{}, fmt.Errorf("unknown static credentials %q", r.Static)
+		}
+		return r.Address, auth, time.Time{}, nil
+	}
+}
+
+// Destroy implements sidecred.Provider.
+func (p *provider) Destroy(ctx context.Context, resource *sidecred.Resource) error {
+	if resource.Metadata == nil {
+		return nil
+	}
+	return p.destroyMetadata(ctx, resource.ID, *resource.Metadata)
+}
+
+// destroyMetadata destroys the credentials from underlying providers (i.e. Artifactory tokens).
+func (p *provider) destroyMetadata(ctx context.Context, id string, metadata sidecred.Metadata) error {
+	for k, v := range metadata {
+		if !strings.HasPrefix(k, "artifactory:") {
+			continue
+		}
+		if p.artifactory == nil {
+			return fmt.Errorf("artifactory is not enabled")
+		}
+		var m sidecred.Metadata
+		if err := json.Unmarshal([]byte(v), &m); err != nil {
+			return fmt.Errorf("unmarshal artifactory metadata: %s", err)
+		}
+		if err := p.artifactory.Destroy(ctx, &sidecred.Resource{
+			Type:     sidecred.ArtifactoryAccessToken,
+			ID:       id,
+			Metadata: &m,
+		}); err != nil {
+			return fmt.Errorf("destroy artifactory token: %s", err)
+		}
+	}
+	return nil
+}
+
+func artifactoryMetadataKey(address string) string {
+	return "artifactory:" + address
+}
+
+func encodeAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// ECRAPI wraps the interface for the API and provides a mocked implementation.
+//
+//counterfeiter:generate . ECRAPI
+type ECRAPI interface {
+	GetAuthorizationToken(input *ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error)
+}
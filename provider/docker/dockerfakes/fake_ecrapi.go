@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package dockerfakes
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/telia-oss/sidecred/provider/docker"
+)
+
+type FakeECRAPI struct {
+	GetAuthorizationTokenStub        func(*ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error)
+	getAuthorizationTokenMutex       sync.RWMutex
+	getAuthorizationTokenArgsForCall []struct {
+		arg1 *ecr.GetAuthorizationTokenInput
+	}
+	getAuthorizationTokenReturns struct {
+		result1 *ecr.GetAuthorizationTokenOutput
+		result2 error
+	}
+	getAuthorizationTokenReturnsOnCall map[int]struct {
+		result1 *ecr.GetAuthorizationTokenOutput
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeECRAPI) GetAuthorizationToken(arg1 *ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error) {
+	fake.getAuthorizationTokenMutex.Lock()
+	ret, specificReturn := fake.getAuthorizationTokenReturnsOnCall[len(fake.getAuthorizationTokenArgsForCall)]
+	fake.getAuthorizationTokenArgsForCall = append(fake.getAuthorizationTokenArgsForCall, struct {
+		arg1 *ecr.GetAuthorizationTokenInput
+	}{arg1})
+	stub := fake.GetAuthorizationTokenStub
+	fakeReturns := fake.getAuthorizationTokenReturns
+	fake.recordInvocation("GetAuthorizationToken", []interface{}{arg1})
+	fake.getAuthorizationTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeECRAPI) GetAuthorizationTokenCallCount() int {
+	fake.getAuthorizationTokenMutex.RLock()
+	defer fake.getAuthorizationTokenMutex.RUnlock()
+	return len(fake.getAuthorizationTokenArgsForCall)
+}
+
+func (fake *FakeECRAPI) GetAuthorizationTokenCalls(stub func(*ecr.GetAuthorizationTokenInput) (*ecr.GetAuthorizationTokenOutput, error)) {
+	fake.getAuthorizationTokenMutex.Lock()
+	defer fake.getAuthorizationTokenMutex.Unlock()
+	fake.GetAuthorizationTokenStub = stub
+}
+
+func (fake *FakeECRAPI) GetAuthorizationTokenArgsForCall(i int) *ecr.GetAuthorizationTokenInput {
+	fake.getAuthorizationTokenMutex.RLock()
+	defer fake.getAuthorizationTokenMutex.RUnlock()
+	argsForCall := fake.getAuthorizationTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeECRAPI) GetAuthorizationTokenReturns(result1 *ecr.GetAuthorizationTokenOutput, result2 error) {
+	fake.getAuthorizationTokenMutex.Lock()
+	defer fake.getAuthorizationTokenMutex.Unlock()
+	fake.GetAuthorizationTokenStub = nil
+	fake.getAuthorizationTokenReturns = struct {
+		result1 *ecr.GetAuthorizationTokenOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeECRAPI) GetAuthorizationTokenReturnsOnCall(i int, result1 *ecr.GetAuthorizationTokenOutput, result2 error) {
+	fake.getAuthorizationTokenMutex.Lock()
+	defer fake.getAuthorizationTokenMutex.Unlock()
+	fake.GetAuthorizationTokenStub = nil
+	if fake.getAuthorizationTokenReturnsOnCall == nil {
+		fake.getAuthorizationTokenReturnsOnCall = make(map[int]struct {
+			result1 *ecr.GetAuthorizationTokenOutput
+			result2 error
+		})
+	}
+	fake.getAuthorizationTokenReturnsOnCall[i] = struct {
+		result1 *ecr.GetAuthorizationTokenOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeECRAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getAuthorizationTokenMutex.RLock()
+	defer fake.getAuthorizationTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeECRAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ docker.ECRAPI = new(FakeECRAPI)
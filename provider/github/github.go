@@ -1,4 +1,4 @@
-// Package github implements a sidecred.Provider for Github access tokens and deploy keys. It also implements
+// Package github implements a sidecred.Provider for Github access tokens, deploy keys and webhook secrets. It also implements
 // a client for Github Apps, which is used to create the supported credentials.
 package github
 
@@ -8,14 +8,20 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"path"
+	"sort"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/google/go-github/v45/github"
 	"github.com/telia-oss/githubapp"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/ssh"
 
 	"github.com/telia-oss/sidecred"
@@ -25,6 +31,8 @@ import (
 var (
 	_ sidecred.Validatable = &DeployKeyRequestConfig{}
 	_ sidecred.Validatable = &AccessTokenRequestConfig{}
+	_ sidecred.Validatable = &WebhookSecretRequestConfig{}
+	_ sidecred.Reconciler  = &provider{}
 )
 
 // DeployKeyRequestConfig ...
@@ -50,8 +58,12 @@ func (c *DeployKeyRequestConfig) Validate() error {
 }
 
 // AccessTokenRequestConfig ...
+// Owners can be used instead of owner to create one access token per installation, and supports glob patterns
+// (e.g. "telia-*") that are matched against the installations of the Github App(s). The token name is a template
+// which can reference the owner, e.g. "{{ .Owner }}-access-token", and must be unique per owner when using owners.
 type AccessTokenRequestConfig struct {
-	Owner        string                 `json:"owner"`
+	Owner        string                 `json:"owner,omitempty"`
+	Owners       []string               `json:"owners,omitempty"`
 	Repositories []string               `json:"repositories,omitempty"`
 	Permissions  *githubapp.Permissions `json:"permissions,omitempty"`
 	TokenName    string                 `json:"token_name,omitempty"`
@@ -59,20 +71,84 @@ type AccessTokenRequestConfig struct {
 
 // Validate implements sidecred.Validatable.
 func (c *AccessTokenRequestConfig) Validate() error {
+	if c.Owner == "" && len(c.Owners) == 0 {
+		return fmt.Errorf("%q must be defined", "owner")
+	}
+	if c.Owner != "" && len(c.Owners) > 0 {
+		return fmt.Errorf("%q and %q are mutually exclusive", "owner", "owners")
+	}
+	if len(c.Owners) > 0 {
+		if len(c.Repositories) > 0 {
+			return fmt.Errorf("%q cannot be used with %q", "repositories", "owners")
+		}
+		for i, o := range c.Owners {
+			if o == "" {
+				return fmt.Errorf("owners[%d]: must be defined", i)
+			}
+			if _, err := path.Match(o, ""); err != nil {
+				return fmt.Errorf("owners[%d]: invalid pattern %q: %s", i, o, err)
+			}
+		}
+		if c.TokenName != "" {
+			a, err := accessTokenName(c.TokenName, "a")
+			if err != nil {
+				return fmt.Errorf("invalid %q: %s", "token_name", err)
+			}
+			b, err := accessTokenName(c.TokenName, "b")
+			if err != nil {
+				return fmt.Errorf("invalid %q: %s", "token_name", err)
+			}
+			if a == b {
+				return fmt.Errorf("%q must include {{ .Owner }} when using %q", "token_name", "owners")
+			}
+		}
+	} else if c.TokenName != "" {
+		if _, err := accessTokenName(c.TokenName, c.Owner); err != nil {
+			return fmt.Errorf("invalid %q: %s", "token_name", err)
+		}
+	}
+	for _, p := range listPermissions(c.Permissions) {
+		if _, ok := permissionLevels[p.level]; !ok {
+			return fmt.Errorf("permissions: %q has unknown access level %q", p.name, p.level)
+		}
+	}
+	return nil
+}
+
+// WebhookSecretRequestConfig ...
+// If repository is omitted, the hook is assumed to be an organization webhook.
+type WebhookSecretRequestConfig struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository,omitempty"`
+	HookID     int64  `json:"hook_id"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *WebhookSecretRequestConfig) Validate() error {
 	if c.Owner == "" {
 		return fmt.Errorf("%q must be defined", "owner")
 	}
+	if c.HookID == 0 {
+		return fmt.Errorf("%q must be defined", "hook_id")
+	}
 	return nil
 }
 
 // New returns a new sidecred.Provider for Github credentials.
 func New(app App, options ...option) sidecred.Provider {
 	p := &provider{
-		app:                 app,
-		keyRotationInterval: time.Hour * 24 * 7,
+		app:                    app,
+		keyRotationInterval:    time.Hour * 24 * 7,
+		secretRotationInterval: time.Hour * 24 * 7,
 		reposClientFactory: func(token string) RepositoriesAPI {
 			return githubapp.NewInstallationClient(token).V3.Repositories
 		},
+		orgsClientFactory: func(token string) OrganizationsAPI {
+			return githubapp.NewInstallationClient(token).V3.Organizations
+		},
+		appsClientFactory: func(token string) AppsAPI {
+			return githubapp.NewInstallationClient(token).V3.Apps
+		},
 		defaultTokenPermissions: &githubapp.Permissions{
 			Metadata:     github.String("read"),
 			Contents:     github.String("read"),
@@ -95,6 +171,30 @@ func WithDeployKeyRotationInterval(duration time.Duration) option {
 	}
 }
 
+// WithWebhookSecretRotationInterval sets the interval at which webhook secrets should be rotated.
+func WithWebhookSecretRotationInterval(duration time.Duration) option {
+	return func(p *provider) {
+		p.secretRotationInterval = duration
+	}
+}
+
+// WithDeployKeyReconciliation enables removal of deploy keys that were created by sidecred for the same credential
+// request (in the same namespace), but which are no longer tracked in state (e.g. due to lost state or a failed
+// Destroy). Stale keys are only removed after the new deploy key has been written to the secret store.
+func WithDeployKeyReconciliation(enabled bool) option {
+	return func(p *provider) {
+		p.reconcileDeployKeys = enabled
+	}
+}
+
+// WithAccessTokenRevocation enables revocation of access tokens when they are replaced or no longer requested.
+// Note that this requires the access token to be stored in the resource metadata (i.e. in the sidecred state).
+func WithAccessTokenRevocation(enabled bool) option {
+	return func(p *provider) {
+		p.revokeAccessTokens = enabled
+	}
+}
+
 // WithReposClientFactory sets the function used to create new installation clients, and can be used to return test fakes.
 func WithReposClientFactory(f func(token string) RepositoriesAPI) option {
 	return func(p *provider) {
@@ -102,11 +202,30 @@ func WithReposClientFactory(f func(token string) RepositoriesAPI) option {
 	}
 }
 
+// WithOrgsClientFactory sets the function used to create new installation clients for the organizations API, and can be used to return test fakes.
+func WithOrgsClientFactory(f func(token string) OrganizationsAPI) option {
+	return func(p *provider) {
+		p.orgsClientFactory = f
+	}
+}
+
+// WithAppsClientFactory sets the function used to create new installation clients for the apps API, and can be used to return test fakes.
+func WithAppsClientFactory(f func(token string) AppsAPI) option {
+	return func(p *provider) {
+		p.appsClientFactory = f
+	}
+}
+
 // Implements sidecred.Provider for Github Credentials.
 type provider struct {
 	app                     App
 	reposClientFactory      func(token string) RepositoriesAPI
+	orgsClientFactory       func(token string) OrganizationsAPI
+	appsClientFactory       func(token string) AppsAPI
 	keyRotationInterval     time.Duration
+	secretRotationInterval  time.Duration
+	reconcileDeployKeys     bool
+	revokeAccessTokens      bool
 	defaultTokenPermissions *githubapp.Permissions
 }
 
@@ -122,6 +241,8 @@ func (p *provider) Create(ctx context.Context, request *sidecred.CredentialReque
 		return p.createDeployKey(ctx, request)
 	case sidecred.GithubAccessToken:
 		return p.createAccessToken(ctx, request)
+	case sidecred.GithubWebhookSecret:
+		return p.createWebhookSecret(ctx, request)
 	default:
 		return nil, nil, fmt.Errorf("invalid request: %s", request.Type)
 	}
@@ -136,22 +257,196 @@ func (p *provider) createAccessToken(ctx context.Context, request *sidecred.Cred
 	if c.Permissions != nil {
 		permissions = c.Permissions
 	}
-	token, err := p.app.CreateInstallationToken(ctx, c.Owner, c.Repositories, permissions)
-	if err != nil {
-		return nil, nil, fmt.Errorf("create access token: %s", err)
+
+	owners := []string{c.Owner}
+	if len(c.Owners) > 0 {
+		var err error
+		owners, err = p.matchInstallations(ctx, c.Owners)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// If token_name is not provided, use default token naming
-	tokenName := c.Owner + "-access-token"
+	tokenNameTemplate := "{{ .Owner }}-access-token"
 	if c.TokenName != "" {
-		tokenName = c.TokenName
+		tokenNameTemplate = c.TokenName
+	}
+
+	var (
+		credentials []*sidecred.Credential
+		metadata    = sidecred.Metadata{}
+	)
+	for _, owner := range owners {
+		tokenName, err := accessTokenName(tokenNameTemplate, owner)
+		if err != nil {
+			return nil, nil, fmt.Errorf("token name: %s", err)
+		}
+		token, err := p.app.CreateInstallationToken(ctx, owner, c.Repositories, permissions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create access token (%s): %s", owner, err)
+		}
+		credentials = append(credentials, &sidecred.Credential{
+			Name:        tokenName,
+			Value:       token.GetToken(),
+			Description: "Github access token managed by sidecred.",
+			Expiration:  token.GetExpiresAt().UTC(),
+		})
+		if p.revokeAccessTokens {
+			metadata[accessTokenMetadataKey(c.Owner, owner)] = token.GetToken()
+		}
+	}
+	if len(metadata) == 0 {
+		return credentials, nil, nil
+	}
+	return credentials, &metadata, nil
+}
+
+// accessTokenName renders the token name template for the given owner.
+func accessTokenName(tmpl, owner string) (string, error) {
+	t, err := template.New("token-name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, struct{ Owner string }{Owner: owner}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// accessTokenMetadataKey returns the metadata key used to store the access token for an owner. Requests
+// for a single owner use "access_token" to remain compatible with existing state.
+func accessTokenMetadataKey(requestOwner, owner string) string {
+	if requestOwner != "" {
+		return "access_token"
 	}
+	return "access_token:" + owner
+}
+
+// matchInstallations returns the owners of the installations that match any of the patterns. Patterns
+// without wildcards are returned as-is, which means that missing installations result in an error
+// when creating the access token.
+func (p *provider) matchInstallations(ctx context.Context, patterns []string) ([]string, error) {
+	var (
+		owners        []string
+		seen          = make(map[string]struct{})
+		installations []string
+	)
+	// Owners are case-insensitive, but explicit owners are passed through as-is since
+	// they can be used in token names (which should match those of the owner field).
+	add := func(owner string) {
+		if _, ok := seen[strings.ToLower(owner)]; !ok {
+			seen[strings.ToLower(owner)] = struct{}{}
+			owners = append(owners, owner)
+		}
+	}
+	for _, pattern := range patterns {
+		if !strings.ContainsAny(pattern, "*?[\\") {
+			add(pattern)
+			continue
+		}
+		if installations == nil {
+			var err error
+			installations, err = p.app.ListInstallations(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("list installations: %s", err)
+			}
+		}
+		for _, i := range installations {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(i)); ok {
+				add(i)
+			}
+		}
+	}
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("no installations matched owners: %s", strings.Join(patterns, ", "))
+	}
+	sort.Strings(owners)
+	return owners, nil
+}
+
+func (p *provider) createWebhookSecret(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c WebhookSecretRequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		repositories []string
+		permissions  = &githubapp.Permissions{
+			OrganizationHooks: github.String("write"), // Used to update organization webhooks: https://docs.github.com/en/rest/overview/permissions-required-for-github-apps#permission-on-organization-hooks
+		}
+	)
+	if c.Repository != "" {
+		repositories = []string{c.Repository}
+		permissions = &githubapp.Permissions{
+			RepositoryHooks: github.String("write"), // Used to update repository webhooks: https://docs.github.com/en/rest/overview/permissions-required-for-github-apps#permission-on-repository-hooks
+		}
+	}
+	token, err := p.app.CreateInstallationToken(ctx, c.Owner, repositories, permissions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create hooks access token: %s", err)
+	}
+
+	secret, err := p.generateSecret()
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate secret: %s", err)
+	}
+
+	hook, err := p.getHook(ctx, token.GetToken(), c.Owner, c.Repository, c.HookID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get webhook: %s", err)
+	}
+
+	// The config has to be passed in its entirety when updating a webhook, otherwise the
+	// other fields (e.g. url and content type) would be unset.
+	config := make(map[string]interface{}, len(hook.Config)+1)
+	for k, v := range hook.Config {
+		config[k] = v
+	}
+	config["secret"] = secret
+
+	if err := p.editHook(ctx, token.GetToken(), c.Owner, c.Repository, c.HookID, &github.Hook{Config: config}); err != nil {
+		return nil, nil, fmt.Errorf("update webhook secret: %s", err)
+	}
+
+	metadata := &sidecred.Metadata{"hook_id": strconv.FormatInt(c.HookID, 10)}
 	return []*sidecred.Credential{{
-		Name:        tokenName,
-		Value:       token.GetToken(),
-		Description: "Github access token managed by sidecred.",
-		Expiration:  token.GetExpiresAt().UTC(),
-	}}, nil, nil
+		Name:        request.Name,
+		Value:       secret,
+		Description: "Github webhook secret managed by sidecred.",
+		Expiration:  time.Now().Add(p.secretRotationInterval).UTC(),
+	}}, metadata, nil
+}
+
+func (p *provider) getHook(ctx context.Context, token, owner, repo string, id int64) (hook *github.Hook, err error) {
+	eventctx.GetStats(ctx).IncGithubCalls()
+	if repo == "" {
+		hook, _, err = p.orgsClientFactory(token).GetHook(ctx, owner, id)
+	} else {
+		hook, _, err = p.reposClientFactory(token).GetHook(ctx, owner, repo, id)
+	}
+	return hook, err
+}
+
+func (p *provider) editHook(ctx context.Context, token, owner, repo string, id int64, hook *github.Hook) (err error) {
+	eventctx.GetStats(ctx).IncGithubCalls()
+	if repo == "" {
+		_, _, err = p.orgsClientFactory(token).EditHook(ctx, owner, id, hook)
+	} else {
+		_, _, err = p.reposClientFactory(token).EditHook(ctx, owner, repo, id, hook)
+	}
+	return err
+}
+
+// generateSecret returns a random hex encoded secret (256 bits).
+func (p *provider) generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 func (p *provider) createDeployKey(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
@@ -171,11 +466,12 @@ func (p *provider) createDeployKey(ctx context.Context, request *sidecred.Creden
 		return nil, nil, fmt.Errorf("generate key pair: %s", err)
 	}
 
+	title := deployKeyTitle(c.Title, eventctx.GetNamespace(ctx), request.Name)
 	key, _, err := p.createKey(ctx, token.GetToken(), c.Owner, c.Repository, &github.Key{
 		ID:       nil,
 		Key:      github.String(publicKey),
 		URL:      nil,
-		Title:    github.String(c.Title),
+		Title:    github.String(title),
 		ReadOnly: github.Bool(c.ReadOnly),
 	})
 	if err != nil {
@@ -191,6 +487,86 @@ func (p *provider) createDeployKey(ctx context.Context, request *sidecred.Creden
 	}}, metadata, nil
 }
 
+// deployKeyTitle returns the title used for deploy keys created by sidecred. The suffix allows
+// us to recognise the keys that are owned by a specific credential request (in a namespace).
+func deployKeyTitle(title, namespace, name string) string {
+	if namespace != "" {
+		name = namespace + "/" + name
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (sidecred:%s)", title, name))
+}
+
+// Reconcile implements sidecred.Reconciler.
+func (p *provider) Reconcile(ctx context.Context, request *sidecred.CredentialRequest, resources []*sidecred.Resource) error {
+	if request.Type != sidecred.GithubDeployKey || !p.reconcileDeployKeys {
+		return nil
+	}
+	var c DeployKeyRequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return err
+	}
+
+	// Keys that are tracked in state (including deposed keys, which are removed by Destroy) are kept.
+	tracked := make(map[int64]struct{})
+	for _, r := range resources {
+		if r.Type != sidecred.GithubDeployKey || r.Metadata == nil {
+			continue
+		}
+		id, err := strconv.ParseInt((*r.Metadata)["key_id"], 10, 64)
+		if err != nil {
+			continue
+		}
+		tracked[id] = struct{}{}
+	}
+
+	token, err := p.app.CreateInstallationToken(ctx, c.Owner, []string{c.Repository}, &githubapp.Permissions{
+		Administration: github.String("write"),
+	})
+	if err != nil {
+		return fmt.Errorf("create administrator access token: %s", err)
+	}
+	title := deployKeyTitle(c.Title, eventctx.GetNamespace(ctx), request.Name)
+	return p.removeStaleKeys(ctx, token.GetToken(), c.Owner, c.Repository, title, tracked)
+}
+
+// removeStaleKeys removes all deploy keys with the given title, except for the keys that are tracked in state.
+func (p *provider) removeStaleKeys(ctx context.Context, token, owner, repo, title string, tracked map[int64]struct{}) error {
+	var (
+		stale       []*github.Key
+		listOptions = &github.ListOptions{PerPage: 100}
+	)
+	for {
+		keys, resp, err := p.listKeys(ctx, token, owner, repo, listOptions)
+		if err != nil {
+			return fmt.Errorf("list deploy keys: %s", err)
+		}
+		for _, k := range keys {
+			if _, ok := tracked[k.GetID()]; k.GetTitle() == title && !ok {
+				stale = append(stale, k)
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		listOptions.Page = resp.NextPage
+	}
+	for _, k := range stale {
+		eventctx.GetLogger(ctx).Info("removing stale deploy key", zap.Int64("keyID", k.GetID()), zap.String("title", title))
+		resp, err := p.deleteKey(ctx, token, owner, repo, k.GetID())
+		if err != nil {
+			if resp == nil || resp.StatusCode != 404 {
+				return fmt.Errorf("delete deploy key (%d): %s", k.GetID(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *provider) listKeys(ctx context.Context, token, owner, repo string, opts *github.ListOptions) ([]*github.Key, *github.Response, error) {
+	eventctx.GetStats(ctx).IncGithubCalls()
+	return p.reposClientFactory(token).ListKeys(ctx, owner, repo, opts)
+}
+
 func (p *provider) createKey(ctx context.Context, token, owner, repo string, key *github.Key) (*github.Key, *github.Response, error) {
 	eventctx.GetStats(ctx).IncGithubCalls()
 	return p.reposClientFactory(token).CreateKey(ctx, owner, repo, key)
@@ -217,6 +593,46 @@ func (p *provider) generateKeyPair() (string, string, error) {
 
 // Destroy implements sidecred.Provider.
 func (p *provider) Destroy(ctx context.Context, resource *sidecred.Resource) error {
+	switch resource.Type {
+	case sidecred.GithubAccessToken:
+		return p.destroyAccessToken(ctx, resource)
+	case sidecred.GithubWebhookSecret:
+		// Webhook secrets cannot be removed without breaking the webhook,
+		// so we leave the last secret in place.
+		return nil
+	default:
+		return p.destroyDeployKey(ctx, resource)
+	}
+}
+
+func (p *provider) destroyAccessToken(ctx context.Context, resource *sidecred.Resource) error {
+	if resource.Metadata == nil {
+		return nil
+	}
+	for key, token := range *resource.Metadata {
+		if key != "access_token" && !strings.HasPrefix(key, "access_token:") {
+			continue
+		}
+		if token == "" {
+			continue
+		}
+		resp, err := p.revokeInstallationToken(ctx, token)
+		if err != nil {
+			// Ignore error if status code is 401 (token has already expired or been revoked)
+			if resp == nil || resp.StatusCode != 401 {
+				return fmt.Errorf("revoke access token: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *provider) revokeInstallationToken(ctx context.Context, token string) (*github.Response, error) {
+	eventctx.GetStats(ctx).IncGithubCalls()
+	return p.appsClientFactory(token).RevokeInstallationToken(ctx)
+}
+
+func (p *provider) destroyDeployKey(ctx context.Context, resource *sidecred.Resource) error {
 	var c DeployKeyRequestConfig
 	if err := json.Unmarshal(resource.Config, &c); err != nil {
 		return fmt.Errorf("unmarshal resource config: %s", err)
@@ -258,6 +674,15 @@ func (p *provider) deleteKey(ctx context.Context, token, owner, repo string, id
 //counterfeiter:generate . App
 type App interface {
 	CreateInstallationToken(ctx context.Context, owner string, repositories []string, permissions *githubapp.Permissions) (*githubapp.Token, error)
+	GetPermissions(ctx context.Context) (map[string]*githubapp.Permissions, error)
+	ListInstallations(ctx context.Context) ([]string, error)
+}
+
+// AppsAPI wraps the Github apps API.
+//
+//counterfeiter:generate . AppsAPI
+type AppsAPI interface {
+	RevokeInstallationToken(ctx context.Context) (*github.Response, error)
 }
 
 // RepositoriesAPI wraps the Github repositories API.
@@ -267,4 +692,14 @@ type RepositoriesAPI interface {
 	ListKeys(ctx context.Context, owner string, repo string, opt *github.ListOptions) ([]*github.Key, *github.Response, error)
 	CreateKey(ctx context.Context, owner string, repo string, key *github.Key) (*github.Key, *github.Response, error)
 	DeleteKey(ctx context.Context, owner string, repo string, id int64) (*github.Response, error)
+	GetHook(ctx context.Context, owner, repo string, id int64) (*github.Hook, *github.Response, error)
+	EditHook(ctx context.Context, owner, repo string, id int64, hook *github.Hook) (*github.Hook, *github.Response, error)
+}
+
+// OrganizationsAPI wraps the Github organizations API.
+//
+//counterfeiter:generate . OrganizationsAPI
+type OrganizationsAPI interface {
+	GetHook(ctx context.Context, org string, id int64) (*github.Hook, *github.Response, error)
+	EditHook(ctx context.Context, org string, id int64, hook *github.Hook) (*github.Hook, *github.Response, error)
 }
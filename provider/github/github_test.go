@@ -2,6 +2,8 @@ package github_test
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/telia-oss/githubapp"
 
 	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/eventctx"
 	provider "github.com/telia-oss/sidecred/provider/github"
 	"github.com/telia-oss/sidecred/provider/github/githubfakes"
 )
@@ -100,3 +103,534 @@ func TestGithubProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubProviderDeployKeyReconciliation(t *testing.T) {
+	var (
+		request = &sidecred.CredentialRequest{
+			Type:   sidecred.GithubDeployKey,
+			Name:   "request-name",
+			Config: []byte(`{"owner":"request-owner","repository":"request-repository","title":"request-title"}`),
+		}
+		title = "request-title (sidecred:team-name/request-name)"
+	)
+
+	tests := []struct {
+		description         string
+		reconcile           bool
+		existingKeys        []*github.Key
+		resources           []*sidecred.Resource
+		expectedListCalls   int
+		expectedDeleteCalls []int64
+	}{
+		{
+			description: "does not list keys by default",
+			existingKeys: []*github.Key{
+				{ID: github.Int64(2), Title: github.String(title)},
+			},
+		},
+		{
+			description: "removes keys for the same request that are not tracked in state",
+			reconcile:   true,
+			existingKeys: []*github.Key{
+				{ID: github.Int64(1), Title: github.String(title)},
+				{ID: github.Int64(2), Title: github.String(title)},
+				{ID: github.Int64(3), Title: github.String(title)},
+				{ID: github.Int64(4), Title: github.String("request-title (sidecred:other-team/request-name)")},
+				{ID: github.Int64(5), Title: github.String("request-title (sidecred:team-name/other-request)")},
+				{ID: github.Int64(6), Title: github.String("request-title")},
+			},
+			resources: []*sidecred.Resource{
+				{Type: sidecred.GithubDeployKey, ID: "request-name", Metadata: &sidecred.Metadata{"key_id": "1"}},
+				{Type: sidecred.GithubDeployKey, ID: "request-name", Metadata: &sidecred.Metadata{"key_id": "2"}, Deposed: true},
+			},
+			expectedListCalls:   1,
+			expectedDeleteCalls: []int64{3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: &github.InstallationToken{Token: github.String("access-token")}}, nil)
+
+			fakeReposAPI := &githubfakes.FakeRepositoriesAPI{}
+			fakeReposAPI.CreateKeyReturns(&github.Key{ID: github.Int64(1), CreatedAt: &github.Timestamp{Time: time.Now()}}, nil, nil)
+			fakeReposAPI.ListKeysReturns(tc.existingKeys, &github.Response{}, nil)
+
+			p := provider.New(fakeApp,
+				provider.WithDeployKeyReconciliation(tc.reconcile),
+				provider.WithReposClientFactory(func(string) provider.RepositoriesAPI {
+					return fakeReposAPI
+				}),
+			)
+			ctx := eventctx.SetNamespace(context.TODO(), "team-name")
+
+			_, _, err := p.Create(ctx, request)
+			require.NoError(t, err)
+
+			_, _, _, key := fakeReposAPI.CreateKeyArgsForCall(0)
+			assert.Equal(t, title, key.GetTitle())
+			assert.Equal(t, 0, fakeReposAPI.ListKeysCallCount(), "keys are not removed before the new key has been stored")
+
+			reconciler, ok := p.(sidecred.Reconciler)
+			require.True(t, ok, "implements sidecred.Reconciler")
+			require.NoError(t, reconciler.Reconcile(ctx, request, tc.resources))
+
+			assert.Equal(t, tc.expectedListCalls, fakeReposAPI.ListKeysCallCount())
+			require.Equal(t, len(tc.expectedDeleteCalls), fakeReposAPI.DeleteKeyCallCount())
+			for i, id := range tc.expectedDeleteCalls {
+				_, _, _, keyID := fakeReposAPI.DeleteKeyArgsForCall(i)
+				assert.Equal(t, id, keyID)
+			}
+		})
+	}
+}
+
+func TestGithubProviderAccessTokenRevocation(t *testing.T) {
+	tests := []struct {
+		description         string
+		revoke              bool
+		revokeResponse      *github.Response
+		revokeError         error
+		expectedMetadata    *sidecred.Metadata
+		expectedRevokeCalls int
+		expectedError       bool
+	}{
+		{
+			description: "does not store or revoke tokens by default",
+		},
+		{
+			description:         "revokes the access token on destroy",
+			revoke:              true,
+			expectedMetadata:    &sidecred.Metadata{"access_token": "access-token"},
+			expectedRevokeCalls: 1,
+		},
+		{
+			description:         "ignores tokens that are already expired",
+			revoke:              true,
+			revokeResponse:      &github.Response{Response: &http.Response{StatusCode: 401}},
+			revokeError:         errors.New("bad credentials"),
+			expectedMetadata:    &sidecred.Metadata{"access_token": "access-token"},
+			expectedRevokeCalls: 1,
+		},
+		{
+			description:         "propagates errors",
+			revoke:              true,
+			revokeError:         errors.New("failure"),
+			expectedMetadata:    &sidecred.Metadata{"access_token": "access-token"},
+			expectedRevokeCalls: 1,
+			expectedError:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: &github.InstallationToken{Token: github.String("access-token")}}, nil)
+
+			fakeAppsAPI := &githubfakes.FakeAppsAPI{}
+			fakeAppsAPI.RevokeInstallationTokenReturns(tc.revokeResponse, tc.revokeError)
+
+			var clientTokens []string
+			p := provider.New(fakeApp,
+				provider.WithAccessTokenRevocation(tc.revoke),
+				provider.WithAppsClientFactory(func(token string) provider.AppsAPI {
+					clientTokens = append(clientTokens, token)
+					return fakeAppsAPI
+				}),
+			)
+
+			request := &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner"}`),
+			}
+			_, metadata, err := p.Create(context.TODO(), request)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, metadata)
+
+			err = p.Destroy(context.TODO(), &sidecred.Resource{
+				Type:     request.Type,
+				ID:       request.Name,
+				Config:   request.Config,
+				Metadata: metadata,
+			})
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRevokeCalls, fakeAppsAPI.RevokeInstallationTokenCallCount())
+			for _, token := range clientTokens {
+				assert.Equal(t, "access-token", token)
+			}
+		})
+	}
+}
+
+func TestGithubProviderAccessTokenOwners(t *testing.T) {
+	tests := []struct {
+		description         string
+		config              string
+		revoke              bool
+		expectedOwners      []string
+		expectedNames       []string
+		expectedMetadata    *sidecred.Metadata
+		expectedRevokeCalls int
+		expectedError       string
+	}{
+		{
+			description:    "creates one token per owner",
+			config:         `{"owners":["telia-oss","itsdalmo"]}`,
+			expectedOwners: []string{"itsdalmo", "telia-oss"},
+			expectedNames:  []string{"itsdalmo-access-token", "telia-oss-access-token"},
+		},
+		{
+			description:    "matches owners against installations",
+			config:         `{"owners":["telia-*"],"token_name":"{{ .Owner }}-ci-token"}`,
+			expectedOwners: []string{"telia-oss", "telia-test"},
+			expectedNames:  []string{"telia-oss-ci-token", "telia-test-ci-token"},
+		},
+		{
+			description:    "stores and revokes all tokens",
+			config:         `{"owners":["telia-*"]}`,
+			revoke:         true,
+			expectedOwners: []string{"telia-oss", "telia-test"},
+			expectedNames:  []string{"telia-oss-access-token", "telia-test-access-token"},
+			expectedMetadata: &sidecred.Metadata{
+				"access_token:telia-oss":  "telia-oss-token",
+				"access_token:telia-test": "telia-test-token",
+			},
+			expectedRevokeCalls: 2,
+		},
+		{
+			description:    "keeps the case of explicit owners",
+			config:         `{"owners":["Telia-OSS","ItsDalmo","TELIA-*"]}`,
+			expectedOwners: []string{"ItsDalmo", "Telia-OSS", "telia-test"},
+			expectedNames:  []string{"ItsDalmo-access-token", "Telia-OSS-access-token", "telia-test-access-token"},
+		},
+		{
+			description:   "fails if no installations match",
+			config:        `{"owners":["unknown-*"]}`,
+			expectedError: "no installations matched owners: unknown-*",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.ListInstallationsReturns([]string{"itsdalmo", "telia-oss", "telia-test"}, nil)
+			fakeApp.CreateInstallationTokenStub = func(_ context.Context, owner string, _ []string, _ *githubapp.Permissions) (*githubapp.Token, error) {
+				return &githubapp.Token{InstallationToken: &github.InstallationToken{Token: github.String(owner + "-token")}}, nil
+			}
+			fakeAppsAPI := &githubfakes.FakeAppsAPI{}
+
+			p := provider.New(fakeApp,
+				provider.WithAccessTokenRevocation(tc.revoke),
+				provider.WithAppsClientFactory(func(token string) provider.AppsAPI {
+					return fakeAppsAPI
+				}),
+			)
+
+			request := &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			}
+			creds, metadata, err := p.Create(context.TODO(), request)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, metadata)
+
+			var names []string
+			for _, c := range creds {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tc.expectedNames, names)
+
+			var owners []string
+			for i := 0; i < fakeApp.CreateInstallationTokenCallCount(); i++ {
+				_, owner, _, _ := fakeApp.CreateInstallationTokenArgsForCall(i)
+				owners = append(owners, owner)
+			}
+			assert.Equal(t, tc.expectedOwners, owners)
+
+			err = p.Destroy(context.TODO(), &sidecred.Resource{
+				Type:     request.Type,
+				ID:       request.Name,
+				Config:   request.Config,
+				Metadata: metadata,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRevokeCalls, fakeAppsAPI.RevokeInstallationTokenCallCount())
+		})
+	}
+}
+
+func TestGithubProviderWebhookSecret(t *testing.T) {
+	hook := &github.Hook{
+		ID: github.Int64(10),
+		Config: map[string]interface{}{
+			"url":          "https://example.com/webhook",
+			"content_type": "json",
+			"secret":       "********",
+		},
+	}
+
+	tests := []struct {
+		description         string
+		config              string
+		expectedPermissions *githubapp.Permissions
+		expectedRepos       []string
+		expectRepoHook      bool
+	}{
+		{
+			description:         "works for repository webhooks",
+			config:              `{"owner":"request-owner","repository":"request-repository","hook_id":10}`,
+			expectedPermissions: &githubapp.Permissions{RepositoryHooks: github.String("write")},
+			expectedRepos:       []string{"request-repository"},
+			expectRepoHook:      true,
+		},
+		{
+			description:         "works for organization webhooks",
+			config:              `{"owner":"request-owner","hook_id":10}`,
+			expectedPermissions: &githubapp.Permissions{OrganizationHooks: github.String("write")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: &github.InstallationToken{Token: github.String("access-token")}}, nil)
+
+			fakeReposAPI := &githubfakes.FakeRepositoriesAPI{}
+			fakeReposAPI.GetHookReturns(hook, nil, nil)
+
+			fakeOrgsAPI := &githubfakes.FakeOrganizationsAPI{}
+			fakeOrgsAPI.GetHookReturns(hook, nil, nil)
+
+			p := provider.New(fakeApp,
+				provider.WithReposClientFactory(func(string) provider.RepositoriesAPI {
+					return fakeReposAPI
+				}),
+				provider.WithOrgsClientFactory(func(string) provider.OrganizationsAPI {
+					return fakeOrgsAPI
+				}),
+			)
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.GithubWebhookSecret,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			require.NoError(t, err)
+			require.Len(t, creds, 1)
+			assert.Equal(t, &sidecred.Metadata{"hook_id": "10"}, metadata)
+			assert.Equal(t, "request-name", creds[0].Name)
+			assert.Len(t, creds[0].Value, 64)
+
+			_, owner, repos, permissions := fakeApp.CreateInstallationTokenArgsForCall(0)
+			assert.Equal(t, "request-owner", owner)
+			assert.Equal(t, tc.expectedRepos, repos)
+			assert.Equal(t, tc.expectedPermissions, permissions)
+
+			var edited *github.Hook
+			if tc.expectRepoHook {
+				require.Equal(t, 1, fakeReposAPI.EditHookCallCount())
+				assert.Equal(t, 0, fakeOrgsAPI.EditHookCallCount())
+				_, _, _, id, h := fakeReposAPI.EditHookArgsForCall(0)
+				assert.Equal(t, int64(10), id)
+				edited = h
+			} else {
+				require.Equal(t, 1, fakeOrgsAPI.EditHookCallCount())
+				assert.Equal(t, 0, fakeReposAPI.EditHookCallCount())
+				_, _, id, h := fakeOrgsAPI.EditHookArgsForCall(0)
+				assert.Equal(t, int64(10), id)
+				edited = h
+			}
+			assert.Equal(t, "https://example.com/webhook", edited.Config["url"])
+			assert.Equal(t, "json", edited.Config["content_type"])
+			assert.Equal(t, creds[0].Value, edited.Config["secret"])
+		})
+	}
+}
+
+func TestGithubProviderValidateRequest(t *testing.T) {
+	granted := &githubapp.Permissions{
+		Metadata:     github.String("read"),
+		Contents:     github.String("read"),
+		PullRequests: github.String("write"),
+		Statuses:     github.String("write"),
+	}
+
+	tests := []struct {
+		description      string
+		request          *sidecred.CredentialRequest
+		permissionsError error
+		expectedError    string
+		expectedInvalid  bool
+	}{
+		{
+			description: "default permissions are validated",
+			request: &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner"}`),
+			},
+		},
+		{
+			description: "errors if the app is missing the requested permission",
+			request: &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner","permissions":{"issues":"read"}}`),
+			},
+			expectedError:   `app 1: missing permission "issues"`,
+			expectedInvalid: true,
+		},
+		{
+			description: "errors if the app has insufficient access",
+			request: &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner","permissions":{"contents":"write"}}`),
+			},
+			expectedError:   `app 1: permission "contents": requested "write" access but only "read" is granted`,
+			expectedInvalid: true,
+		},
+		{
+			description: "api errors do not invalidate the request",
+			request: &sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner"}`),
+			},
+			permissionsError: errors.New("502 Bad Gateway"),
+			expectedError:    "get app permissions: 502 Bad Gateway",
+		},
+		{
+			description: "does not validate deploy keys",
+			request: &sidecred.CredentialRequest{
+				Type:   sidecred.GithubDeployKey,
+				Name:   "request-name",
+				Config: []byte(`{"owner":"request-owner","repository":"request-repository","title":"request-title"}`),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.GetPermissionsReturns(map[string]*githubapp.Permissions{"1": granted}, tc.permissionsError)
+
+			p := provider.New(fakeApp)
+			err := p.(sidecred.RequestValidator).ValidateRequest(context.TODO(), tc.request)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				var invalid *sidecred.InvalidRequestError
+				assert.Equal(t, tc.expectedInvalid, errors.As(err, &invalid), "invalid request error")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGithubProviderLintRequest(t *testing.T) {
+	tests := []struct {
+		description      string
+		config           string
+		expectedWarnings []string
+	}{
+		{
+			description: "does not warn for default permissions",
+			config:      `{"owner":"request-owner"}`,
+		},
+		{
+			description: "does not warn for read access",
+			config:      `{"owner":"request-owner","permissions":{"contents":"read","metadata":"read"}}`,
+		},
+		{
+			description: "warns once for all permissions with more than read access",
+			config:      `{"owner":"request-owner","permissions":{"contents":"write","issues":"write","metadata":"read"}}`,
+			expectedWarnings: []string{
+				"requests more than read access for contents (write), issues (write), consider using read if the token does not modify resources",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			p := provider.New(&githubfakes.FakeApp{})
+			warnings := p.(sidecred.RequestLinter).LintRequest(&sidecred.CredentialRequest{
+				Type:   sidecred.GithubAccessToken,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			assert.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestAccessTokenRequestConfig(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "valid config",
+			config:      `{"owner":"request-owner","token_name":"{{ .Owner }}-token"}`,
+		},
+		{
+			description: "valid owners config",
+			config:      `{"owners":["telia-*","itsdalmo"],"token_name":"{{ .Owner }}-token"}`,
+		},
+		{
+			description:   "unknown access level",
+			config:        `{"owner":"request-owner","permissions":{"contents":"wirte"}}`,
+			expectedError: `permissions: "contents" has unknown access level "wirte"`,
+		},
+		{
+			description:   "requires an owner",
+			config:        `{}`,
+			expectedError: `"owner" must be defined`,
+		},
+		{
+			description:   "owner and owners are mutually exclusive",
+			config:        `{"owner":"request-owner","owners":["telia-*"]}`,
+			expectedError: `"owner" and "owners" are mutually exclusive`,
+		},
+		{
+			description:   "owners cannot be combined with repositories",
+			config:        `{"owners":["telia-*"],"repositories":["sidecred"]}`,
+			expectedError: `"repositories" cannot be used with "owners"`,
+		},
+		{
+			description:   "invalid owner patterns",
+			config:        `{"owners":["telia-["]}`,
+			expectedError: `owners[0]: invalid pattern "telia-[": syntax error in pattern`,
+		},
+		{
+			description:   "token name must be unique per owner",
+			config:        `{"owners":["telia-*"],"token_name":"access-token"}`,
+			expectedError: `"token_name" must include {{ .Owner }} when using "owners"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &provider.AccessTokenRequestConfig{}
+			require.NoError(t, sidecred.UnmarshalConfig([]byte(tc.config), c))
+			err := c.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package githubfakes
+
+import (
+	"context"
+	"sync"
+
+	githuba "github.com/google/go-github/v45/github"
+	"github.com/telia-oss/sidecred/provider/github"
+)
+
+type FakeAppsAPI struct {
+	RevokeInstallationTokenStub        func(context.Context) (*githuba.Response, error)
+	revokeInstallationTokenMutex       sync.RWMutex
+	revokeInstallationTokenArgsForCall []struct {
+		arg1 context.Context
+	}
+	revokeInstallationTokenReturns struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	revokeInstallationTokenReturnsOnCall map[int]struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationToken(arg1 context.Context) (*githuba.Response, error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	ret, specificReturn := fake.revokeInstallationTokenReturnsOnCall[len(fake.revokeInstallationTokenArgsForCall)]
+	fake.revokeInstallationTokenArgsForCall = append(fake.revokeInstallationTokenArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.RevokeInstallationTokenStub
+	fakeReturns := fake.revokeInstallationTokenReturns
+	fake.recordInvocation("RevokeInstallationToken", []interface{}{arg1})
+	fake.revokeInstallationTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationTokenCallCount() int {
+	fake.revokeInstallationTokenMutex.RLock()
+	defer fake.revokeInstallationTokenMutex.RUnlock()
+	return len(fake.revokeInstallationTokenArgsForCall)
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationTokenCalls(stub func(context.Context) (*githuba.Response, error)) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = stub
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationTokenArgsForCall(i int) context.Context {
+	fake.revokeInstallationTokenMutex.RLock()
+	defer fake.revokeInstallationTokenMutex.RUnlock()
+	argsForCall := fake.revokeInstallationTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationTokenReturns(result1 *githuba.Response, result2 error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = nil
+	fake.revokeInstallationTokenReturns = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppsAPI) RevokeInstallationTokenReturnsOnCall(i int, result1 *githuba.Response, result2 error) {
+	fake.revokeInstallationTokenMutex.Lock()
+	defer fake.revokeInstallationTokenMutex.Unlock()
+	fake.RevokeInstallationTokenStub = nil
+	if fake.revokeInstallationTokenReturnsOnCall == nil {
+		fake.revokeInstallationTokenReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Response
+			result2 error
+		})
+	}
+	fake.revokeInstallationTokenReturnsOnCall[i] = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAppsAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.revokeInstallationTokenMutex.RLock()
+	defer fake.revokeInstallationTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAppsAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ github.AppsAPI = new(FakeAppsAPI)
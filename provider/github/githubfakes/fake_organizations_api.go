@@ -0,0 +1,217 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package githubfakes
+
+import (
+	"context"
+	"sync"
+
+	githuba "github.com/google/go-github/v45/github"
+	"github.com/telia-oss/sidecred/provider/github"
+)
+
+type FakeOrganizationsAPI struct {
+	EditHookStub        func(context.Context, string, int64, *githuba.Hook) (*githuba.Hook, *githuba.Response, error)
+	editHookMutex       sync.RWMutex
+	editHookArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+		arg4 *githuba.Hook
+	}
+	editHookReturns struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	editHookReturnsOnCall map[int]struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	GetHookStub        func(context.Context, string, int64) (*githuba.Hook, *githuba.Response, error)
+	getHookMutex       sync.RWMutex
+	getHookArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}
+	getHookReturns struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	getHookReturnsOnCall map[int]struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeOrganizationsAPI) EditHook(arg1 context.Context, arg2 string, arg3 int64, arg4 *githuba.Hook) (*githuba.Hook, *githuba.Response, error) {
+	fake.editHookMutex.Lock()
+	ret, specificReturn := fake.editHookReturnsOnCall[len(fake.editHookArgsForCall)]
+	fake.editHookArgsForCall = append(fake.editHookArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+		arg4 *githuba.Hook
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.EditHookStub
+	fakeReturns := fake.editHookReturns
+	fake.recordInvocation("EditHook", []interface{}{arg1, arg2, arg3, arg4})
+	fake.editHookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOrganizationsAPI) EditHookCallCount() int {
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	return len(fake.editHookArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) EditHookCalls(stub func(context.Context, string, int64, *githuba.Hook) (*githuba.Hook, *githuba.Response, error)) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) EditHookArgsForCall(i int) (context.Context, string, int64, *githuba.Hook) {
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	argsForCall := fake.editHookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeOrganizationsAPI) EditHookReturns(result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = nil
+	fake.editHookReturns = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) EditHookReturnsOnCall(i int, result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = nil
+	if fake.editHookReturnsOnCall == nil {
+		fake.editHookReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Hook
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.editHookReturnsOnCall[i] = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetHook(arg1 context.Context, arg2 string, arg3 int64) (*githuba.Hook, *githuba.Response, error) {
+	fake.getHookMutex.Lock()
+	ret, specificReturn := fake.getHookReturnsOnCall[len(fake.getHookArgsForCall)]
+	fake.getHookArgsForCall = append(fake.getHookArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int64
+	}{arg1, arg2, arg3})
+	stub := fake.GetHookStub
+	fakeReturns := fake.getHookReturns
+	fake.recordInvocation("GetHook", []interface{}{arg1, arg2, arg3})
+	fake.getHookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOrganizationsAPI) GetHookCallCount() int {
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
+	return len(fake.getHookArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) GetHookCalls(stub func(context.Context, string, int64) (*githuba.Hook, *githuba.Response, error)) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) GetHookArgsForCall(i int) (context.Context, string, int64) {
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
+	argsForCall := fake.getHookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOrganizationsAPI) GetHookReturns(result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = nil
+	fake.getHookReturns = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetHookReturnsOnCall(i int, result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = nil
+	if fake.getHookReturnsOnCall == nil {
+		fake.getHookReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Hook
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getHookReturnsOnCall[i] = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeOrganizationsAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ github.OrganizationsAPI = new(FakeOrganizationsAPI)
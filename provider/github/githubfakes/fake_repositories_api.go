@@ -44,6 +44,43 @@ type FakeRepositoriesAPI struct {
 		result1 *githuba.Response
 		result2 error
 	}
+	EditHookStub        func(context.Context, string, string, int64, *githuba.Hook) (*githuba.Hook, *githuba.Response, error)
+	editHookMutex       sync.RWMutex
+	editHookArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+		arg5 *githuba.Hook
+	}
+	editHookReturns struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	editHookReturnsOnCall map[int]struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	GetHookStub        func(context.Context, string, string, int64) (*githuba.Hook, *githuba.Response, error)
+	getHookMutex       sync.RWMutex
+	getHookArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+	}
+	getHookReturns struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
+	getHookReturnsOnCall map[int]struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}
 	ListKeysStub        func(context.Context, string, string, *githuba.ListOptions) ([]*githuba.Key, *githuba.Response, error)
 	listKeysMutex       sync.RWMutex
 	listKeysArgsForCall []struct {
@@ -203,6 +240,147 @@ func (fake *FakeRepositoriesAPI) DeleteKeyReturnsOnCall(i int, result1 *githuba.
 	}{result1, result2}
 }
 
+func (fake *FakeRepositoriesAPI) EditHook(arg1 context.Context, arg2 string, arg3 string, arg4 int64, arg5 *githuba.Hook) (*githuba.Hook, *githuba.Response, error) {
+	fake.editHookMutex.Lock()
+	ret, specificReturn := fake.editHookReturnsOnCall[len(fake.editHookArgsForCall)]
+	fake.editHookArgsForCall = append(fake.editHookArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+		arg5 *githuba.Hook
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.EditHookStub
+	fakeReturns := fake.editHookReturns
+	fake.recordInvocation("EditHook", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.editHookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositoriesAPI) EditHookCallCount() int {
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	return len(fake.editHookArgsForCall)
+}
+
+func (fake *FakeRepositoriesAPI) EditHookCalls(stub func(context.Context, string, string, int64, *githuba.Hook) (*githuba.Hook, *githuba.Response, error)) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = stub
+}
+
+func (fake *FakeRepositoriesAPI) EditHookArgsForCall(i int) (context.Context, string, string, int64, *githuba.Hook) {
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	argsForCall := fake.editHookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeRepositoriesAPI) EditHookReturns(result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = nil
+	fake.editHookReturns = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesAPI) EditHookReturnsOnCall(i int, result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.editHookMutex.Lock()
+	defer fake.editHookMutex.Unlock()
+	fake.EditHookStub = nil
+	if fake.editHookReturnsOnCall == nil {
+		fake.editHookReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Hook
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.editHookReturnsOnCall[i] = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesAPI) GetHook(arg1 context.Context, arg2 string, arg3 string, arg4 int64) (*githuba.Hook, *githuba.Response, error) {
+	fake.getHookMutex.Lock()
+	ret, specificReturn := fake.getHookReturnsOnCall[len(fake.getHookArgsForCall)]
+	fake.getHookArgsForCall = append(fake.getHookArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetHookStub
+	fakeReturns := fake.getHookReturns
+	fake.recordInvocation("GetHook", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getHookMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeRepositoriesAPI) GetHookCallCount() int {
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
+	return len(fake.getHookArgsForCall)
+}
+
+func (fake *FakeRepositoriesAPI) GetHookCalls(stub func(context.Context, string, string, int64) (*githuba.Hook, *githuba.Response, error)) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = stub
+}
+
+func (fake *FakeRepositoriesAPI) GetHookArgsForCall(i int) (context.Context, string, string, int64) {
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
+	argsForCall := fake.getHookArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRepositoriesAPI) GetHookReturns(result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = nil
+	fake.getHookReturns = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeRepositoriesAPI) GetHookReturnsOnCall(i int, result1 *githuba.Hook, result2 *githuba.Response, result3 error) {
+	fake.getHookMutex.Lock()
+	defer fake.getHookMutex.Unlock()
+	fake.GetHookStub = nil
+	if fake.getHookReturnsOnCall == nil {
+		fake.getHookReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Hook
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getHookReturnsOnCall[i] = struct {
+		result1 *githuba.Hook
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeRepositoriesAPI) ListKeys(arg1 context.Context, arg2 string, arg3 string, arg4 *githuba.ListOptions) ([]*githuba.Key, *githuba.Response, error) {
 	fake.listKeysMutex.Lock()
 	ret, specificReturn := fake.listKeysReturnsOnCall[len(fake.listKeysArgsForCall)]
@@ -280,6 +458,10 @@ func (fake *FakeRepositoriesAPI) Invocations() map[string][][]interface{} {
 	defer fake.createKeyMutex.RUnlock()
 	fake.deleteKeyMutex.RLock()
 	defer fake.deleteKeyMutex.RUnlock()
+	fake.editHookMutex.RLock()
+	defer fake.editHookMutex.RUnlock()
+	fake.getHookMutex.RLock()
+	defer fake.getHookMutex.RUnlock()
 	fake.listKeysMutex.RLock()
 	defer fake.listKeysMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/telia-oss/githubapp"
+
+	"github.com/telia-oss/sidecred"
+)
+
+var (
+	_ sidecred.RequestValidator = &provider{}
+	_ sidecred.RequestLinter    = &provider{}
+)
+
+// permissionLevels ranks the access levels that can be granted for a permission.
+var permissionLevels = map[string]int{
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+// permission is a single (named) permission from githubapp.Permissions.
+type permission struct {
+	name  string
+	level string
+}
+
+// listPermissions returns the permissions that are set, named after
+// their JSON field (i.e. the name used in the request config).
+func listPermissions(p *githubapp.Permissions) []permission {
+	if p == nil {
+		return nil
+	}
+	var (
+		out []permission
+		v   = reflect.ValueOf(p).Elem()
+	)
+	for i := 0; i < v.NumField(); i++ {
+		level, ok := v.Field(i).Interface().(*string)
+		if !ok || level == nil {
+			continue
+		}
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		out = append(out, permission{name: name, level: *level})
+	}
+	return out
+}
+
+// validatePermissions returns an error if the requested permissions are not granted.
+func validatePermissions(requested, granted *githubapp.Permissions) error {
+	levels := make(map[string]string)
+	for _, p := range listPermissions(granted) {
+		levels[p.name] = p.level
+	}
+	for _, p := range listPermissions(requested) {
+		level, ok := levels[p.name]
+		if !ok {
+			return fmt.Errorf("missing permission %q", p.name)
+		}
+		if permissionLevels[level] < permissionLevels[p.level] {
+			return fmt.Errorf("permission %q: requested %q access but only %q is granted", p.name, p.level, level)
+		}
+	}
+	return nil
+}
+
+// ValidateRequest implements sidecred.RequestValidator.
+func (p *provider) ValidateRequest(ctx context.Context, request *sidecred.CredentialRequest) error {
+	if request.Type != sidecred.GithubAccessToken {
+		return nil
+	}
+	var c AccessTokenRequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return &sidecred.InvalidRequestError{Err: err}
+	}
+	permissions := p.defaultTokenPermissions
+	if c.Permissions != nil {
+		permissions = c.Permissions
+	}
+
+	// Errors from the API are returned as-is, since they are not caused by the request.
+	apps, err := p.app.GetPermissions(ctx)
+	if err != nil {
+		return fmt.Errorf("get app permissions: %s", err)
+	}
+	ids := make([]string, 0, len(apps))
+	for id := range apps {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := validatePermissions(permissions, apps[id]); err != nil {
+			return &sidecred.InvalidRequestError{Err: fmt.Errorf("app %s: %s", id, err)}
+		}
+	}
+	return nil
+}
+
+// LintRequest implements sidecred.RequestLinter.
+func (p *provider) LintRequest(request *sidecred.CredentialRequest) []string {
+	if request.Type != sidecred.GithubAccessToken {
+		return nil
+	}
+	var c AccessTokenRequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil
+	}
+	var names []string
+	for _, pp := range listPermissions(c.Permissions) {
+		if permissionLevels[pp.level] > permissionLevels["read"] {
+			names = append(names, fmt.Sprintf("%s (%s)", pp.name, pp.level))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("requests more than read access for %s, consider using read if the token does not modify resources", strings.Join(names, ", "))}
+}
@@ -0,0 +1,315 @@
+// Package jwt implements a sidecred.Provider for JSON Web Tokens (https://www.rfc-editor.org/rfc/rfc7519)
+// signed with a configured RSA, ECDSA or Ed25519 key.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/telia-oss/sidecred"
+)
+
+var _ sidecred.Validatable = &RequestConfig{}
+
+// Registered claims that are set by the provider, and cannot be specified as custom claims.
+var registeredClaims = map[string]struct{}{
+	"iss": {},
+	"sub": {},
+	"aud": {},
+	"exp": {},
+	"nbf": {},
+	"iat": {},
+	"jti": {},
+}
+
+// RequestConfig ...
+// The issuer defaults to the issuer configured for the provider (if any). Claims are added to
+// the token as-is, and cannot override the registered claims (e.g. "exp" or "sub").
+//
+// The following shows an example resource configuration as YAML:
+//
+//	creds:
+//	  - type: jwt:token
+//	    name: inventory-api-token
+//	    config:
+//	      subject: deployment-pipeline
+//	      audience:
+//	        - inventory-api
+//	      claims:
+//	        scope: read write
+//	      ttl: 30m
+type RequestConfig struct {
+	Issuer   string                 `json:"issuer,omitempty"`
+	Subject  string                 `json:"subject"`
+	Audience []string               `json:"audience,omitempty"`
+	Claims   map[string]interface{} `json:"claims,omitempty"`
+	TTL      *sidecred.Duration     `json:"ttl,omitempty"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *RequestConfig) Validate() error {
+	if c.Subject == "" {
+		return fmt.Errorf("%q must be defined", "subject")
+	}
+	for i, a := range c.Audience {
+		if a == "" {
+			return fmt.Errorf("audience[%d]: must not be empty", i)
+		}
+	}
+	for k := range c.Claims {
+		if _, found := registeredClaims[k]; found {
+			return fmt.Errorf("claims: %q is a registered claim and cannot be overridden", k)
+		}
+	}
+	if c.TTL != nil && c.TTL.Duration <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "ttl")
+	}
+	return nil
+}
+
+// ParsePrivateKey parses a PEM encoded RSA, ECDSA or Ed25519 private key (PKCS1, SEC1 or PKCS8).
+func ParsePrivateKey(privateKey []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// New returns a new sidecred.Provider for JSON Web Tokens signed with the given key.
+func New(key crypto.Signer, options ...option) sidecred.Provider {
+	p := &provider{
+		key:    key,
+		ttl:    1 * time.Hour,
+		maxTTL: 24 * time.Hour,
+	}
+	for _, optionFunc := range options {
+		optionFunc(p)
+	}
+	return p
+}
+
+type option func(*provider)
+
+// WithIssuer sets the default issuer ("iss") of tokens.
+func WithIssuer(issuer string) option {
+	return func(p *provider) {
+		p.issuer = issuer
+	}
+}
+
+// WithKeyID sets the key ID ("kid") in the header of tokens, which allows consumers to look up the public key (e.g. from a JWKS).
+func WithKeyID(keyID string) option {
+	return func(p *provider) {
+		p.keyID = keyID
+	}
+}
+
+// WithTokenTTL overrides the default TTL of tokens.
+func WithTokenTTL(ttl time.Duration) option {
+	return func(p *provider) {
+		p.ttl = ttl
+	}
+}
+
+// WithMaxTokenTTL sets the maximum TTL that can be requested for tokens.
+func WithMaxTokenTTL(ttl time.Duration) option {
+	return func(p *provider) {
+		p.maxTTL = ttl
+	}
+}
+
+type provider struct {
+	key    crypto.Signer
+	issuer string
+	keyID  string
+	ttl    time.Duration
+	maxTTL time.Duration
+}
+
+// Type implements sidecred.Provider.
+func (p *provider) Type() sidecred.ProviderType {
+	return sidecred.JWT
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(_ context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c RequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+	issuer := p.issuer
+	if c.Issuer != "" {
+		issuer = c.Issuer
+	}
+	if issuer == "" {
+		return nil, nil, fmt.Errorf("%q must be defined (no default issuer is configured)", "issuer")
+	}
+	ttl := p.ttl
+	if c.TTL != nil {
+		ttl = c.TTL.Duration
+	}
+	if ttl > p.maxTTL {
+		return nil, nil, fmt.Errorf("requested ttl (%s) exceeds the maximum ttl (%s)", ttl, p.maxTTL)
+	}
+
+	id, err := generateID()
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate id: %s", err)
+	}
+	var (
+		now     = time.Now()
+		expires = now.Add(ttl).Unix()
+		claims  = make(map[string]interface{}, len(c.Claims)+7)
+	)
+	for k, v := range c.Claims {
+		claims[k] = v
+	}
+	claims["iss"] = issuer
+	claims["sub"] = c.Subject
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = expires
+	claims["jti"] = id
+	switch len(c.Audience) {
+	case 0:
+	case 1:
+		claims["aud"] = c.Audience[0]
+	default:
+		claims["aud"] = c.Audience
+	}
+
+	token, err := p.sign(claims)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign token: %s", err)
+	}
+	return []*sidecred.Credential{{
+		Name:        request.Name,
+		Value:       token,
+		Description: "JSON Web Token managed by sidecred.",
+		Expiration:  time.Unix(expires, 0).UTC(),
+	}}, nil, nil
+}
+
+// sign returns the signed and encoded token (JWS compact serialization).
+func (p *provider) sign(claims map[string]interface{}) (string, error) {
+	algorithm, hash, err := signingAlgorithm(p.key)
+	if err != nil {
+		return "", err
+	}
+	header := map[string]string{"alg": algorithm, "typ": "JWT"}
+	if p.keyID != "" {
+		header["kid"] = p.keyID
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("marshal header: %s", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("marshal claims: %s", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+	switch key := p.key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(input))
+	case *ecdsa.PrivateKey:
+		// JWS uses the fixed size concatenation of R and S rather than ASN.1 (RFC 7518, section 3.4).
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest(hash, input))
+		if err != nil {
+			return "", err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+	default:
+		signature, err = p.key.Sign(rand.Reader, digest(hash, input), hash)
+		if err != nil {
+			return "", err
+		}
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Destroy implements sidecred.Provider.
+func (p *provider) Destroy(_ context.Context, _ *sidecred.Resource) error {
+	// Tokens cannot be revoked, they expire on their own.
+	return nil
+}
+
+// signingAlgorithm returns the JWS algorithm and hash for a key.
+func signingAlgorithm(key crypto.Signer) (string, crypto.Hash, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", crypto.SHA256, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return "ES256", crypto.SHA256, nil
+		case 384:
+			return "ES384", crypto.SHA384, nil
+		case 521:
+			return "ES512", crypto.SHA512, nil
+		}
+		return "", 0, fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return "EdDSA", 0, nil
+	}
+	return "", 0, fmt.Errorf("unsupported key type %T", key)
+}
+
+func digest(hash crypto.Hash, input string) []byte {
+	switch hash {
+	case crypto.SHA384:
+		h := sha512.Sum384([]byte(input))
+		return h[:]
+	case crypto.SHA512:
+		h := sha512.Sum512([]byte(input))
+		return h[:]
+	}
+	h := sha256.Sum256([]byte(input))
+	return h[:]
+}
+
+// generateID returns a random token ID ("jti").
+func generateID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
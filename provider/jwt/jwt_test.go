@@ -0,0 +1,176 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	provider "github.com/telia-oss/sidecred/provider/jwt"
+)
+
+func TestJWTProvider(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		description       string
+		key               crypto.Signer
+		config            string
+		expectedAlgorithm string
+		expectedClaims    map[string]interface{}
+		expectedTTL       time.Duration
+		expectedError     string
+	}{
+		{
+			description:       "signs tokens with rsa keys",
+			key:               rsaKey,
+			config:            `{"subject":"pipeline","audience":["inventory-api"],"claims":{"scope":"read"}}`,
+			expectedAlgorithm: "RS256",
+			expectedClaims: map[string]interface{}{
+				"iss":   "https://sidecred.example.com",
+				"sub":   "pipeline",
+				"aud":   "inventory-api",
+				"scope": "read",
+			},
+			expectedTTL: time.Hour,
+		},
+		{
+			description:       "signs tokens with ecdsa keys",
+			key:               ecdsaKey,
+			config:            `{"issuer":"custom","subject":"pipeline","audience":["a","b"],"ttl":"30m"}`,
+			expectedAlgorithm: "ES384",
+			expectedClaims: map[string]interface{}{
+				"iss": "custom",
+				"sub": "pipeline",
+				"aud": []interface{}{"a", "b"},
+			},
+			expectedTTL: 30 * time.Minute,
+		},
+		{
+			description:       "signs tokens with ed25519 keys",
+			key:               ed25519Key,
+			config:            `{"subject":"pipeline","claims":{"groups":["admin"]}}`,
+			expectedAlgorithm: "EdDSA",
+			expectedClaims: map[string]interface{}{
+				"iss":    "https://sidecred.example.com",
+				"sub":    "pipeline",
+				"groups": []interface{}{"admin"},
+			},
+			expectedTTL: time.Hour,
+		},
+		{
+			description:   "registered claims cannot be overridden",
+			key:           ed25519Key,
+			config:        `{"subject":"pipeline","claims":{"exp":0}}`,
+			expectedError: `invalid config: claims: "exp" is a registered claim and cannot be overridden`,
+		},
+		{
+			description:   "ttl cannot exceed the maximum",
+			key:           ed25519Key,
+			config:        `{"subject":"pipeline","ttl":"3h"}`,
+			expectedError: "requested ttl (3h0m0s) exceeds the maximum ttl (2h0m0s)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			p := provider.New(tc.key,
+				provider.WithIssuer("https://sidecred.example.com"),
+				provider.WithKeyID("key-1"),
+				provider.WithMaxTokenTTL(2*time.Hour),
+			)
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.JWTToken,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, creds, 1)
+			assert.Nil(t, metadata)
+			assert.Equal(t, "request-name", creds[0].Name)
+
+			parts := strings.Split(creds[0].Value, ".")
+			require.Len(t, parts, 3)
+
+			var header map[string]string
+			decodeSegment(t, parts[0], &header)
+			assert.Equal(t, map[string]string{"alg": tc.expectedAlgorithm, "typ": "JWT", "kid": "key-1"}, header)
+
+			var claims map[string]interface{}
+			decodeSegment(t, parts[1], &claims)
+			for k, v := range tc.expectedClaims {
+				assert.Equal(t, v, claims[k], k)
+			}
+			assert.NotEmpty(t, claims["jti"])
+			assert.Equal(t, creds[0].Expiration, time.Unix(int64(claims["exp"].(float64)), 0).UTC())
+			assert.WithinDuration(t, time.Now().Add(tc.expectedTTL), creds[0].Expiration, 5*time.Second)
+
+			signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+			require.NoError(t, err)
+			assert.True(t, verify(tc.key.Public(), parts[0]+"."+parts[1], signature), "valid signature")
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	b, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	signer, err := provider.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}))
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), signer.Public())
+
+	_, err = provider.ParsePrivateKey([]byte("not a key"))
+	assert.EqualError(t, err, "no private key found")
+}
+
+func decodeSegment(t *testing.T, segment string, v interface{}) {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, v))
+}
+
+func verify(key crypto.PublicKey, input string, signature []byte) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		h := sha256.Sum256([]byte(input))
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], signature) == nil
+	case *ecdsa.PublicKey:
+		h := sha512.Sum384([]byte(input))
+		size := len(signature) / 2
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, h[:], r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, []byte(input), signature)
+	}
+	return false
+}
@@ -2,30 +2,247 @@
 package random
 
 import (
+	"bufio"
 	"context"
-	"math/rand"
+	"crypto/rand"
+	_ "embed" // Used to embed the passphrase wordlist.
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/telia-oss/sidecred"
 )
 
+// Format of the random secret.
+type Format string
+
+// Enumeration of supported formats.
+const (
+	FormatString     Format = "string"
+	FormatUUID       Format = "uuid"
+	FormatHex        Format = "hex"
+	FormatBase64     Format = "base64"
+	FormatPassphrase Format = "passphrase"
+	FormatBcrypt     Format = "bcrypt"
+	FormatHtpasswd   Format = "htpasswd"
+)
+
+// effLargeWordlist is the EFF large wordlist for diceware passphrases (CC BY 3.0):
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordlist string
+
+var wordlist = parseWordlist(effLargeWordlist)
+
+// parseWordlist returns the words from a diceware wordlist ("<dice rolls>\t<word>" per line).
+func parseWordlist(s string) []string {
+	var (
+		words   []string
+		scanner = bufio.NewScanner(strings.NewReader(s))
+	)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			words = append(words, fields[1])
+		}
+	}
+	return words
+}
+
 var _ sidecred.Validatable = &RequestConfig{}
 
+const (
+	lowercase = "abcdefghijklmnopqrstuvwxyz"
+	uppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits    = "0123456789"
+	special   = "!@#$%&*"
+
+	// DefaultCharset is used when charset is omitted from the request config.
+	DefaultCharset = lowercase + uppercase + digits + special
+
+	// MaxLength is the maximum length of a random string (or number of bytes for hex and base64).
+	MaxLength = 1024
+
+	// MaxWords is the maximum number of words in a passphrase.
+	MaxWords = 64
+
+	// maxBcryptLength is the maximum password length supported by bcrypt.
+	maxBcryptLength = 72
+)
+
 // RequestConfig ...
+// The format decides how length is interpreted:
+//   - string (default): the number of characters, see below for the charset options.
+//   - uuid: a (version 4) UUID, length must be omitted.
+//   - hex and base64: the number of random bytes that are encoded.
+//   - passphrase: the number of words (diceware), separated by separator (defaults to "-").
+//   - bcrypt and htpasswd: a random string (like the string format) which is written as "<name>", along with
+//     its bcrypt hash as "<name>-bcrypt", or a htpasswd entry for username as "<name>-htpasswd".
+//
+// The charset defaults to DefaultCharset, and characters in exclude are removed from the charset (e.g. to
+// avoid ambiguous characters like "0O1l"). The minimum counts apply to the characters of each class that
+// remain in the charset, where special characters are any characters that are not letters or digits.
 type RequestConfig struct {
-	Length int `json:"length"`
+	Format           Format             `json:"format,omitempty"`
+	Length           int                `json:"length,omitempty"`
+	Charset          string             `json:"charset,omitempty"`
+	Exclude          string             `json:"exclude,omitempty"`
+	MinLowercase     int                `json:"min_lowercase,omitempty"`
+	MinUppercase     int                `json:"min_uppercase,omitempty"`
+	MinDigits        int                `json:"min_digits,omitempty"`
+	MinSpecial       int                `json:"min_special,omitempty"`
+	Separator        string             `json:"separator,omitempty"`
+	Username         string             `json:"username,omitempty"`
+	RotationInterval *sidecred.Duration `json:"rotation_interval,omitempty"`
 }
 
 // Validate implements sidecred.Validatable.
 func (c *RequestConfig) Validate() error {
+	if c.RotationInterval != nil && c.RotationInterval.Duration <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "rotation_interval")
+	}
+	if c.Separator != "" && c.format() != FormatPassphrase {
+		return fmt.Errorf("%q is only supported for format %q", "separator", FormatPassphrase)
+	}
+	if c.Username != "" && c.format() != FormatHtpasswd {
+		return fmt.Errorf("%q is only supported for format %q", "username", FormatHtpasswd)
+	}
+	switch c.format() {
+	case FormatString:
+		return c.validateString()
+	case FormatBcrypt, FormatHtpasswd:
+		if c.format() == FormatHtpasswd {
+			if c.Username == "" {
+				return fmt.Errorf("%q must be defined", "username")
+			}
+			if strings.Contains(c.Username, ":") {
+				return fmt.Errorf("%q cannot contain %q", "username", ":")
+			}
+		}
+		if c.Length > maxBcryptLength {
+			return fmt.Errorf("%q must be less than or equal to %d for format %q", "length", maxBcryptLength, c.format())
+		}
+		return c.validateString()
+	case FormatUUID:
+		if c.Length != 0 {
+			return fmt.Errorf("%q is not supported for format %q", "length", FormatUUID)
+		}
+	case FormatHex, FormatBase64:
+		if c.Length <= 0 || c.Length > MaxLength {
+			return fmt.Errorf("%q must be between 1 and %d (bytes) for format %q", "length", MaxLength, c.format())
+		}
+	case FormatPassphrase:
+		if c.Length <= 0 || c.Length > MaxWords {
+			return fmt.Errorf("%q must be between 1 and %d (words) for format %q", "length", MaxWords, c.format())
+		}
+	default:
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+	if c.hasCharsetOptions() {
+		return fmt.Errorf("charset options are not supported for format %q", c.format())
+	}
+	return nil
+}
+
+// format returns the requested format, or the default format.
+func (c *RequestConfig) format() Format {
+	if c.Format == "" {
+		return FormatString
+	}
+	return c.Format
+}
+
+// hasCharsetOptions returns true if any options that only apply to random strings are set.
+func (c *RequestConfig) hasCharsetOptions() bool {
+	return c.Charset != "" || c.Exclude != "" || c.MinLowercase != 0 || c.MinUppercase != 0 || c.MinDigits != 0 || c.MinSpecial != 0
+}
+
+// validateString validates the options for random strings.
+func (c *RequestConfig) validateString() error {
+	if c.Length <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "length")
+	}
+	if c.Length > MaxLength {
+		return fmt.Errorf("%q must be less than or equal to %d", "length", MaxLength)
+	}
+	charset := c.charset()
+	if charset == "" {
+		return fmt.Errorf("%q is empty after removing excluded characters", "charset")
+	}
+	var total int
+	for _, class := range c.classes(charset) {
+		if class.min < 0 {
+			return fmt.Errorf("%q must not be negative", class.name)
+		}
+		if class.min > 0 && class.chars == "" {
+			return fmt.Errorf("%q is set, but the charset contains no such characters", class.name)
+		}
+		total += class.min
+	}
+	if total > c.Length {
+		return fmt.Errorf("sum of minimum character counts (%d) exceeds %q (%d)", total, "length", c.Length)
+	}
 	return nil
 }
 
+// charset returns the unique characters in the charset, minus the excluded characters.
+func (c *RequestConfig) charset() string {
+	charset := c.Charset
+	if charset == "" {
+		charset = DefaultCharset
+	}
+	var (
+		b    strings.Builder
+		seen = make(map[rune]struct{})
+	)
+	for _, r := range charset {
+		if _, ok := seen[r]; ok || strings.ContainsRune(c.Exclude, r) {
+			continue
+		}
+		seen[r] = struct{}{}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type characterClass struct {
+	name  string
+	chars string
+	min   int
+}
+
+// classes returns the characters of the charset that belong to each character class.
+func (c *RequestConfig) classes(charset string) []characterClass {
+	classes := []characterClass{
+		{name: "min_lowercase", min: c.MinLowercase},
+		{name: "min_uppercase", min: c.MinUppercase},
+		{name: "min_digits", min: c.MinDigits},
+		{name: "min_special", min: c.MinSpecial},
+	}
+	for _, r := range charset {
+		i := 3
+		switch {
+		case strings.ContainsRune(lowercase, r):
+			i = 0
+		case strings.ContainsRune(uppercase, r):
+			i = 1
+		case strings.ContainsRune(digits, r):
+			i = 2
+		}
+		classes[i].chars += string(r)
+	}
+	return classes
+}
+
 // New returns a new sidecred.Provider for random strings.
-func New(seed int64, options ...option) sidecred.Provider {
+func New(options ...option) sidecred.Provider {
 	p := &provider{
-		generator:        rand.New(rand.NewSource(seed)),
-		chars:            "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%&*",
 		rotationInterval: time.Hour * 24 * 7,
 	}
 	for _, optionFunc := range options {
@@ -44,8 +261,6 @@ func WithRotationInterval(duration time.Duration) option {
 }
 
 type provider struct {
-	generator        *rand.Rand
-	chars            string
 	rotationInterval time.Duration
 }
 
@@ -60,18 +275,159 @@ func (p *provider) Create(ctx context.Context, request *sidecred.CredentialReque
 	if err := request.UnmarshalConfig(&c); err != nil {
 		return nil, nil, err
 	}
-	b := make([]byte, c.Length)
-	for i := range b {
-		b[i] = p.chars[p.generator.Intn(len(p.chars))]
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+	rotationInterval := p.rotationInterval
+	if c.RotationInterval != nil {
+		rotationInterval = c.RotationInterval.Duration
 	}
-	return []*sidecred.Credential{
-		{
-			Name:        request.Name,
-			Value:       string(b),
+
+	values, err := generate(request.Name, &c)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate %s: %s", c.format(), err)
+	}
+
+	var (
+		credentials = make([]*sidecred.Credential, 0, len(values))
+		expiration  = time.Now().Add(rotationInterval).UTC()
+	)
+	for _, v := range values {
+		credentials = append(credentials, &sidecred.Credential{
+			Name:        v.name,
+			Value:       v.value,
 			Description: "Random generated secret managed by Sidecred.",
-			Expiration:  time.Now().Add(p.rotationInterval).UTC(),
-		},
-	}, nil, nil
+			Expiration:  expiration,
+		})
+	}
+	return credentials, nil, nil
+}
+
+type namedValue struct {
+	name  string
+	value string
+}
+
+// generate the value(s) for the (validated) request config.
+func generate(name string, c *RequestConfig) ([]namedValue, error) {
+	var (
+		value string
+		err   error
+	)
+	switch c.format() {
+	case FormatUUID:
+		value, err = generateUUID()
+	case FormatHex:
+		var b []byte
+		if b, err = generateBytes(c.Length); err == nil {
+			value = hex.EncodeToString(b)
+		}
+	case FormatBase64:
+		var b []byte
+		if b, err = generateBytes(c.Length); err == nil {
+			value = base64.StdEncoding.EncodeToString(b)
+		}
+	case FormatPassphrase:
+		value, err = generatePassphrase(c.Length, c.Separator)
+	default:
+		value, err = generateString(c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := []namedValue{{name: name, value: value}}
+	switch c.format() {
+	case FormatBcrypt, FormatHtpasswd:
+		hash, err := bcrypt.GenerateFromPassword([]byte(value), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("bcrypt: %s", err)
+		}
+		if c.format() == FormatBcrypt {
+			values = append(values, namedValue{name: name + "-bcrypt", value: string(hash)})
+		} else {
+			values = append(values, namedValue{name: name + "-htpasswd", value: c.Username + ":" + string(hash)})
+		}
+	}
+	return values, nil
+}
+
+func generateBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// generateUUID returns a random (version 4) UUID.
+func generateUUID() (string, error) {
+	b, err := generateBytes(16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// generatePassphrase returns a diceware passphrase with the given number of words.
+func generatePassphrase(words int, separator string) (string, error) {
+	if separator == "" {
+		separator = "-"
+	}
+	out := make([]string, words)
+	for i := range out {
+		n, err := randomInt(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		out[i] = wordlist[n]
+	}
+	return strings.Join(out, separator), nil
+}
+
+// generateString returns a random string that satisfies the (validated) request config.
+func generateString(c *RequestConfig) (string, error) {
+	var (
+		charset = []rune(c.charset())
+		out     = make([]rune, 0, c.Length)
+	)
+	for _, class := range c.classes(string(charset)) {
+		chars := []rune(class.chars)
+		for i := 0; i < class.min; i++ {
+			n, err := randomInt(len(chars))
+			if err != nil {
+				return "", err
+			}
+			out = append(out, chars[n])
+		}
+	}
+	for len(out) < c.Length {
+		n, err := randomInt(len(charset))
+		if err != nil {
+			return "", err
+		}
+		out = append(out, charset[n])
+	}
+	// Shuffle (Fisher-Yates) so that the required characters are not always at the start of the string.
+	for i := len(out) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out), nil
+}
+
+// randomInt returns a uniform random integer in [0, n).
+func randomInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
 }
 
 // Destroy implements sidecred.Provider.
@@ -2,10 +2,17 @@ package random_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/telia-oss/sidecred"
 	provider "github.com/telia-oss/sidecred/provider/random"
@@ -14,67 +21,279 @@ import (
 func TestRandomProvider(t *testing.T) {
 	tests := []struct {
 		description string
-		seed        int64
-		request     *sidecred.CredentialRequest
-		expected    []*sidecred.Credential
+		config      string
+		validate    func(t *testing.T, value string)
 	}{
 		{
 			description: "random provider works",
-			seed:        1,
-			request: &sidecred.CredentialRequest{
-				Type: sidecred.Randomized,
-				Name: "request-name",
+			config:      `{"length":32}`,
+			validate: func(t *testing.T, value string) {
+				assert.Len(t, value, 32)
+				assertCharset(t, provider.DefaultCharset, value)
 			},
-			expected: []*sidecred.Credential{{
-				Name:        "request-name",
-				Value:       "",
-				Description: "Random generated secret managed by Sidecred.",
-			}},
 		},
 		{
-			description: "we can set the length of the random string",
-			seed:        1,
-			request: &sidecred.CredentialRequest{
-				Type:   sidecred.Randomized,
-				Name:   "request-name",
-				Config: []byte(`{"length":5}`),
+			description: "we can set the charset",
+			config:      `{"length":64,"charset":"abc"}`,
+			validate: func(t *testing.T, value string) {
+				assert.Len(t, value, 64)
+				assertCharset(t, "abc", value)
+			},
+		},
+		{
+			description: "we can exclude characters",
+			config:      `{"length":64,"charset":"abc123","exclude":"a1"}`,
+			validate: func(t *testing.T, value string) {
+				assert.Len(t, value, 64)
+				assertCharset(t, "bc23", value)
 			},
-			expected: []*sidecred.Credential{{
-				Name:        "request-name",
-				Value:       "1TrAn",
-				Description: "Random generated secret managed by Sidecred.",
-			}},
 		},
 		{
-			description: "we can control the seed",
-			seed:        2,
-			request: &sidecred.CredentialRequest{
+			description: "we can require a minimum number of characters per class",
+			config:      `{"length":8,"min_lowercase":2,"min_uppercase":2,"min_digits":2,"min_special":2}`,
+			validate: func(t *testing.T, value string) {
+				assert.Len(t, value, 8)
+				assert.Equal(t, 2, count(value, unicode.IsLower))
+				assert.Equal(t, 2, count(value, unicode.IsUpper))
+				assert.Equal(t, 2, count(value, unicode.IsDigit))
+				assert.Equal(t, 2, count(value, func(r rune) bool { return strings.ContainsRune("!@#$%&*", r) }))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			p := provider.New()
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
 				Type:   sidecred.Randomized,
 				Name:   "request-name",
-				Config: []byte(`{"length":5}`),
+				Config: []byte(tc.config),
+			})
+			require.NoError(t, err)
+			require.Len(t, creds, 1)
+			assert.Nil(t, metadata)
+			assert.Equal(t, "request-name", creds[0].Name)
+			assert.Equal(t, "Random generated secret managed by Sidecred.", creds[0].Description)
+			tc.validate(t, creds[0].Value)
+		})
+	}
+}
+
+func TestRandomProviderFormats(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        string
+		expectedNames []string
+		validate      func(t *testing.T, values []string)
+	}{
+		{
+			description:   "uuid",
+			config:        `{"format":"uuid"}`,
+			expectedNames: []string{"request-name"},
+			validate: func(t *testing.T, values []string) {
+				assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), values[0])
+			},
+		},
+		{
+			description:   "hex",
+			config:        `{"format":"hex","length":32}`,
+			expectedNames: []string{"request-name"},
+			validate: func(t *testing.T, values []string) {
+				b, err := hex.DecodeString(values[0])
+				require.NoError(t, err)
+				assert.Len(t, b, 32)
+			},
+		},
+		{
+			description:   "base64",
+			config:        `{"format":"base64","length":32}`,
+			expectedNames: []string{"request-name"},
+			validate: func(t *testing.T, values []string) {
+				b, err := base64.StdEncoding.DecodeString(values[0])
+				require.NoError(t, err)
+				assert.Len(t, b, 32)
+			},
+		},
+		{
+			description:   "passphrase",
+			config:        `{"format":"passphrase","length":6,"separator":" "}`,
+			expectedNames: []string{"request-name"},
+			validate: func(t *testing.T, values []string) {
+				words := strings.Split(values[0], " ")
+				assert.Len(t, words, 6)
+				for _, w := range words {
+					assert.NotEmpty(t, w)
+				}
+			},
+		},
+		{
+			description:   "bcrypt",
+			config:        `{"format":"bcrypt","length":20}`,
+			expectedNames: []string{"request-name", "request-name-bcrypt"},
+			validate: func(t *testing.T, values []string) {
+				assert.Len(t, values[0], 20)
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(values[1]), []byte(values[0])))
+			},
+		},
+		{
+			description:   "htpasswd",
+			config:        `{"format":"htpasswd","length":20,"username":"concourse"}`,
+			expectedNames: []string{"request-name", "request-name-htpasswd"},
+			validate: func(t *testing.T, values []string) {
+				parts := strings.SplitN(values[1], ":", 2)
+				require.Len(t, parts, 2)
+				assert.Equal(t, "concourse", parts[0])
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(parts[1]), []byte(values[0])))
 			},
-			expected: []*sidecred.Credential{{
-				Name:        "request-name",
-				Value:       "bsviM",
-				Description: "Random generated secret managed by Sidecred.",
-			}},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.description, func(t *testing.T) {
-			p := provider.New(tc.seed)
+			p := provider.New()
 
-			creds, metadata, err := p.Create(context.TODO(), tc.request)
+			creds, _, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.Randomized,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
 			require.NoError(t, err)
-			require.Len(t, creds, len(tc.expected))
-			assert.Nil(t, metadata)
 
-			for i, e := range tc.expected {
-				assert.Equal(t, e.Name, creds[i].Name)
-				assert.Equal(t, e.Value, creds[i].Value)
-				assert.Equal(t, e.Description, creds[i].Description)
+			var names, values []string
+			for _, c := range creds {
+				names = append(names, c.Name)
+				values = append(values, c.Value)
 			}
+			assert.Equal(t, tc.expectedNames, names)
+			tc.validate(t, values)
 		})
 	}
 }
+
+func TestRandomProviderRotationInterval(t *testing.T) {
+	p := provider.New(provider.WithRotationInterval(time.Hour))
+
+	creds, _, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+		Type:   sidecred.Randomized,
+		Name:   "request-name",
+		Config: []byte(`{"length":16,"rotation_interval":"720h"}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	assert.WithinDuration(t, time.Now().Add(720*time.Hour), creds[0].Expiration, time.Minute)
+}
+
+func TestRandomProviderIsRandom(t *testing.T) {
+	var (
+		p    = provider.New()
+		seen = make(map[string]struct{})
+	)
+	for i := 0; i < 10; i++ {
+		creds, _, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+			Type:   sidecred.Randomized,
+			Name:   "request-name",
+			Config: []byte(`{"length":16}`),
+		})
+		require.NoError(t, err)
+		seen[creds[0].Value] = struct{}{}
+	}
+	assert.Len(t, seen, 10)
+}
+
+func TestRequestConfig(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "valid config",
+			config:      `{"length":16,"min_digits":2}`,
+		},
+		{
+			description:   "length is required",
+			config:        `{}`,
+			expectedError: `"length" must be greater than zero`,
+		},
+		{
+			description:   "length has an upper bound",
+			config:        `{"length":100000}`,
+			expectedError: `"length" must be less than or equal to 1024`,
+		},
+		{
+			description:   "charset cannot be empty",
+			config:        `{"length":16,"charset":"abc","exclude":"cba"}`,
+			expectedError: `"charset" is empty after removing excluded characters`,
+		},
+		{
+			description:   "minimum counts must be satisfiable",
+			config:        `{"length":16,"charset":"abc","min_digits":1}`,
+			expectedError: `"min_digits" is set, but the charset contains no such characters`,
+		},
+		{
+			description:   "unknown format",
+			config:        `{"format":"rot13","length":16}`,
+			expectedError: `unknown format "rot13"`,
+		},
+		{
+			description:   "uuid does not support length",
+			config:        `{"format":"uuid","length":16}`,
+			expectedError: `"length" is not supported for format "uuid"`,
+		},
+		{
+			description:   "hex requires a length",
+			config:        `{"format":"hex"}`,
+			expectedError: `"length" must be between 1 and 1024 (bytes) for format "hex"`,
+		},
+		{
+			description:   "charset options are only supported for strings",
+			config:        `{"format":"passphrase","length":6,"charset":"abc"}`,
+			expectedError: `charset options are not supported for format "passphrase"`,
+		},
+		{
+			description:   "htpasswd requires a username",
+			config:        `{"format":"htpasswd","length":16}`,
+			expectedError: `"username" must be defined`,
+		},
+		{
+			description:   "bcrypt has a maximum length",
+			config:        `{"format":"bcrypt","length":100}`,
+			expectedError: `"length" must be less than or equal to 72 for format "bcrypt"`,
+		},
+		{
+			description:   "minimum counts cannot exceed the length",
+			config:        `{"length":4,"min_lowercase":3,"min_digits":3}`,
+			expectedError: `sum of minimum character counts (6) exceeds "length" (4)`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &provider.RequestConfig{}
+			require.NoError(t, sidecred.UnmarshalConfig([]byte(tc.config), c))
+			err := c.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func assertCharset(t *testing.T, charset, value string) {
+	t.Helper()
+	for _, r := range value {
+		assert.Contains(t, charset, string(r))
+	}
+}
+
+func count(s string, f func(rune) bool) (n int) {
+	for _, r := range s {
+		if f(r) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,295 @@
+// Package ssh implements a sidecred.Provider for SSH certificates signed by a certificate authority (CA).
+package ssh
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/telia-oss/sidecred"
+)
+
+var _ sidecred.Validatable = &RequestConfig{}
+
+// Enumeration of supported certificate types.
+const (
+	UserCertificate = "user"
+	HostCertificate = "host"
+)
+
+// Enumeration of supported key types.
+const (
+	KeyTypeED25519 = "ed25519"
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeRSA     = "rsa"
+)
+
+// RequestConfig ...
+// Certificates default to user certificates with the "permit-pty" extension, and key_id defaults to the
+// name of the credential request. Extensions are only supported for user certificates.
+type RequestConfig struct {
+	CertificateType string             `json:"certificate_type,omitempty"`
+	KeyType         string             `json:"key_type,omitempty"`
+	KeyID           string             `json:"key_id,omitempty"`
+	Principals      []string           `json:"principals"`
+	Extensions      map[string]string  `json:"extensions,omitempty"`
+	CriticalOptions map[string]string  `json:"critical_options,omitempty"`
+	Duration        *sidecred.Duration `json:"duration,omitempty"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *RequestConfig) Validate() error {
+	if len(c.Principals) == 0 {
+		return fmt.Errorf("%q must be defined", "principals")
+	}
+	for i, p := range c.Principals {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("principals[%d]: must be defined", i)
+		}
+	}
+	switch c.CertificateType {
+	case "", UserCertificate:
+	case HostCertificate:
+		if len(c.Extensions) > 0 {
+			return fmt.Errorf("%q are not supported for host certificates", "extensions")
+		}
+	default:
+		return fmt.Errorf("unknown certificate type %q", c.CertificateType)
+	}
+	switch c.KeyType {
+	case "", KeyTypeED25519, KeyTypeECDSA, KeyTypeRSA:
+	default:
+		return fmt.Errorf("unknown key type %q", c.KeyType)
+	}
+	if c.Duration != nil && c.Duration.Duration <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "duration")
+	}
+	return nil
+}
+
+// New returns a new sidecred.Provider for SSH certificates signed by the given CA.
+func New(ca cryptossh.Signer, options ...option) sidecred.Provider {
+	p := &provider{
+		ca:                  ca,
+		certificateDuration: 1 * time.Hour,
+		maxDuration:         24 * time.Hour,
+		clockSkew:           5 * time.Minute,
+	}
+	for _, optionFunc := range options {
+		optionFunc(p)
+	}
+	return p
+}
+
+type option func(*provider)
+
+// WithCertificateDuration overrides the default duration (validity) of certificates.
+func WithCertificateDuration(duration time.Duration) option {
+	return func(p *provider) {
+		p.certificateDuration = duration
+	}
+}
+
+// WithMaxCertificateDuration sets the maximum duration that can be requested for certificates.
+func WithMaxCertificateDuration(duration time.Duration) option {
+	return func(p *provider) {
+		p.maxDuration = duration
+	}
+}
+
+type provider struct {
+	ca                  cryptossh.Signer
+	certificateDuration time.Duration
+	maxDuration         time.Duration
+	clockSkew           time.Duration
+}
+
+// Type implements sidecred.Provider.
+func (p *provider) Type() sidecred.ProviderType {
+	return sidecred.SSH
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c RequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+	duration := p.certificateDuration
+	if c.Duration != nil {
+		duration = c.Duration.Duration
+	}
+	if duration > p.maxDuration {
+		return nil, nil, fmt.Errorf("requested duration (%s) exceeds the maximum duration (%s)", duration, p.maxDuration)
+	}
+
+	key, privateKey, err := generateKey(c.KeyType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key: %s", err)
+	}
+	publicKey, err := cryptossh.NewPublicKey(key.Public())
+	if err != nil {
+		return nil, nil, fmt.Errorf("convert public key: %s", err)
+	}
+
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return nil, nil, fmt.Errorf("generate serial: %s", err)
+	}
+
+	keyID := c.KeyID
+	if keyID == "" {
+		keyID = request.Name
+	}
+	certType, extensions := uint32(cryptossh.UserCert), c.Extensions
+	if c.CertificateType == HostCertificate {
+		certType = cryptossh.HostCert
+	} else if extensions == nil {
+		extensions = map[string]string{"permit-pty": ""}
+	}
+
+	now := time.Now()
+	cert := &cryptossh.Certificate{
+		Key:             publicKey,
+		Serial:          binary.BigEndian.Uint64(serial),
+		CertType:        certType,
+		KeyId:           keyID,
+		ValidPrincipals: c.Principals,
+		// Backdate the certificate slightly to allow for clock skew.
+		ValidAfter:  uint64(now.Add(-p.clockSkew).Unix()),
+		ValidBefore: uint64(now.Add(duration).Unix()),
+		Permissions: cryptossh.Permissions{
+			CriticalOptions: c.CriticalOptions,
+			Extensions:      extensions,
+		},
+	}
+	if err := cert.SignCert(rand.Reader, p.ca); err != nil {
+		return nil, nil, fmt.Errorf("sign certificate: %s", err)
+	}
+
+	expiration := time.Unix(int64(cert.ValidBefore), 0).UTC()
+	metadata := &sidecred.Metadata{"serial": fmt.Sprintf("%d", cert.Serial)}
+	return []*sidecred.Credential{
+		{
+			Name:        request.Name + "-private-key",
+			Value:       privateKey,
+			Description: "SSH private key managed by sidecred.",
+			Expiration:  expiration,
+		},
+		{
+			Name:        request.Name + "-certificate",
+			Value:       strings.TrimSpace(string(cryptossh.MarshalAuthorizedKey(cert))),
+			Description: "SSH certificate managed by sidecred.",
+			Expiration:  expiration,
+		},
+	}, metadata, nil
+}
+
+// Destroy implements sidecred.Provider.
+func (p *provider) Destroy(_ context.Context, _ *sidecred.Resource) error {
+	// Certificates cannot be revoked without distributing a revocation list, so
+	// we rely on the certificates being short-lived.
+	return nil
+}
+
+// generateKey returns a new key pair and the PEM encoded private key.
+func generateKey(keyType string) (crypto.Signer, string, error) {
+	switch keyType {
+	case KeyTypeECDSA:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, "", err
+		}
+		b, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, "", err
+		}
+		return key, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})), nil
+	case KeyTypeRSA:
+		key, err := rsa.GenerateKey(rand.Reader, 3072)
+		if err != nil {
+			return nil, "", err
+		}
+		return key, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})), nil
+	default:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, "", err
+		}
+		privateKey, err := marshalED25519PrivateKey(key)
+		if err != nil {
+			return nil, "", err
+		}
+		return key, privateKey, nil
+	}
+}
+
+// marshalED25519PrivateKey returns the key in the (unencrypted) OpenSSH private key format, since
+// ed25519 keys cannot be encoded as PEM in a format that is understood by (all versions of) OpenSSH.
+// See: https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key
+func marshalED25519PrivateKey(key ed25519.PrivateKey) (string, error) {
+	check := make([]byte, 4)
+	if _, err := rand.Read(check); err != nil {
+		return "", err
+	}
+	publicKey := key.Public().(ed25519.PublicKey)
+
+	privateBlock := struct {
+		Check1  uint32
+		Check2  uint32
+		KeyType string
+		Public  []byte
+		Private []byte
+		Comment string
+		Pad     []byte `ssh:"rest"`
+	}{
+		Check1:  binary.BigEndian.Uint32(check),
+		Check2:  binary.BigEndian.Uint32(check),
+		KeyType: cryptossh.KeyAlgoED25519,
+		Public:  publicKey,
+		Private: key,
+	}
+	// The private block is padded to the cipher block size (8 for "none") with the bytes 1, 2, 3, ...
+	length := len(cryptossh.Marshal(privateBlock))
+	for i := 0; (length+i)%8 != 0; i++ {
+		privateBlock.Pad = append(privateBlock.Pad, byte(i+1))
+	}
+
+	sshPublicKey, err := cryptossh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	b := cryptossh.Marshal(struct {
+		CipherName   string
+		KdfName      string
+		KdfOptions   string
+		NumKeys      uint32
+		PublicKey    []byte
+		PrivateBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PublicKey:    sshPublicKey.Marshal(),
+		PrivateBlock: cryptossh.Marshal(privateBlock),
+	})
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "OPENSSH PRIVATE KEY",
+		Bytes: append([]byte("openssh-key-v1\x00"), b...),
+	})), nil
+}
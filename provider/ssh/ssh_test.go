@@ -0,0 +1,179 @@
+package ssh_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/telia-oss/sidecred"
+	provider "github.com/telia-oss/sidecred/provider/ssh"
+)
+
+func TestSSHProvider(t *testing.T) {
+	tests := []struct {
+		description        string
+		config             string
+		expectedCertType   uint32
+		expectedKeyType    string
+		expectedKeyID      string
+		expectedExtensions map[string]string
+		expectedDuration   time.Duration
+		expectedError      string
+	}{
+		{
+			description:        "ssh provider works",
+			config:             `{"principals":["concourse"]}`,
+			expectedCertType:   cryptossh.UserCert,
+			expectedKeyType:    cryptossh.KeyAlgoED25519,
+			expectedKeyID:      "request-name",
+			expectedExtensions: map[string]string{"permit-pty": ""},
+			expectedDuration:   time.Hour,
+		},
+		{
+			description:        "supports user certificate options",
+			config:             `{"principals":["concourse"],"key_type":"ecdsa","key_id":"ci","extensions":{"permit-port-forwarding":""},"duration":"2h"}`,
+			expectedCertType:   cryptossh.UserCert,
+			expectedKeyType:    cryptossh.KeyAlgoECDSA256,
+			expectedKeyID:      "ci",
+			expectedExtensions: map[string]string{"permit-port-forwarding": ""},
+			expectedDuration:   2 * time.Hour,
+		},
+		{
+			description:        "supports host certificates",
+			config:             `{"principals":["bastion.example.com"],"certificate_type":"host","key_type":"rsa"}`,
+			expectedCertType:   cryptossh.HostCert,
+			expectedKeyType:    cryptossh.KeyAlgoRSA,
+			expectedKeyID:      "request-name",
+			expectedExtensions: map[string]string{},
+			expectedDuration:   time.Hour,
+		},
+		{
+			description:   "enforces the maximum duration",
+			config:        `{"principals":["concourse"],"duration":"48h"}`,
+			expectedError: "requested duration (48h0m0s) exceeds the maximum duration (24h0m0s)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			ca := newTestCA(t)
+			p := provider.New(ca)
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.SSHCertificate,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, creds, 2)
+			assert.Equal(t, "request-name-private-key", creds[0].Name)
+			assert.Equal(t, "request-name-certificate", creds[1].Name)
+
+			key, err := cryptossh.ParsePrivateKey([]byte(creds[0].Value))
+			require.NoError(t, err)
+
+			pub, _, _, _, err := cryptossh.ParseAuthorizedKey([]byte(creds[1].Value))
+			require.NoError(t, err)
+			cert, ok := pub.(*cryptossh.Certificate)
+			require.True(t, ok, "is certificate")
+
+			assert.Equal(t, key.PublicKey().Marshal(), cert.Key.Marshal())
+			assert.Equal(t, tc.expectedKeyType, cert.Key.Type())
+			assert.Equal(t, tc.expectedCertType, cert.CertType)
+			assert.Equal(t, tc.expectedKeyID, cert.KeyId)
+			assert.Equal(t, tc.expectedExtensions, cert.Extensions)
+			assert.Equal(t, ca.PublicKey().Marshal(), cert.SignatureKey.Marshal())
+			assert.WithinDuration(t, time.Now().Add(tc.expectedDuration), time.Unix(int64(cert.ValidBefore), 0), time.Minute)
+			assert.Equal(t, time.Unix(int64(cert.ValidBefore), 0).UTC(), creds[0].Expiration)
+			assert.NotEmpty(t, (*metadata)["serial"])
+
+			checker := &cryptossh.CertChecker{
+				IsUserAuthority: func(auth cryptossh.PublicKey) bool { return string(auth.Marshal()) == string(ca.PublicKey().Marshal()) },
+				IsHostAuthority: func(auth cryptossh.PublicKey, _ string) bool {
+					return string(auth.Marshal()) == string(ca.PublicKey().Marshal())
+				},
+			}
+			if tc.expectedCertType == cryptossh.HostCert {
+				assert.NoError(t, checker.CheckHostKey(cert.ValidPrincipals[0]+":22", &net.TCPAddr{}, cert))
+			} else {
+				_, err = checker.Authenticate(testConnMetadata{user: cert.ValidPrincipals[0]}, cert)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRequestConfig(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "valid config",
+			config:      `{"principals":["concourse"]}`,
+		},
+		{
+			description:   "requires principals",
+			config:        `{}`,
+			expectedError: `"principals" must be defined`,
+		},
+		{
+			description:   "unknown certificate type",
+			config:        `{"principals":["concourse"],"certificate_type":"machine"}`,
+			expectedError: `unknown certificate type "machine"`,
+		},
+		{
+			description:   "unknown key type",
+			config:        `{"principals":["concourse"],"key_type":"dsa"}`,
+			expectedError: `unknown key type "dsa"`,
+		},
+		{
+			description:   "host certificates do not support extensions",
+			config:        `{"principals":["concourse"],"certificate_type":"host","extensions":{"permit-pty":""}}`,
+			expectedError: `"extensions" are not supported for host certificates`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &provider.RequestConfig{}
+			require.NoError(t, sidecred.UnmarshalConfig([]byte(tc.config), c))
+			err := c.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T) cryptossh.Signer {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := cryptossh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer
+}
+
+type testConnMetadata struct {
+	cryptossh.ConnMetadata
+	user string
+}
+
+func (m testConnMetadata) User() string {
+	return m.user
+}
@@ -0,0 +1,209 @@
+// Package vault implements a sidecred.Provider for (dynamic) secrets from HashiCorp Vault, e.g. the
+// database or AWS secrets engines. See https://www.vaultproject.io/docs/secrets for details.
+package vault
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/telia-oss/sidecred"
+	vaultapi "github.com/telia-oss/sidecred/internal/vault"
+)
+
+var _ sidecred.Validatable = &RequestConfig{}
+
+// RequestConfig ...
+// The secret at path is read from Vault, or written to (e.g. POST/PUT endpoints like aws/sts/<role>)
+// when data is specified. Fields maps fields in the response data to credential names, and
+// defaults to writing every field as "<name>-<field>".
+//
+// The following shows an example resource configuration as YAML:
+//
+//	creds:
+//	  - type: vault:secret
+//	    name: database
+//	    config:
+//	      path: database/creds/readonly
+//	      fields:
+//	        username: database-username
+//	        password: database-password
+type RequestConfig struct {
+	Path   string                 `json:"path"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Fields map[string]string      `json:"fields,omitempty"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *RequestConfig) Validate() error {
+	if c.Path == "" {
+		return fmt.Errorf("%q must be defined", "path")
+	}
+	names := make(map[string]struct{}, len(c.Fields))
+	for field, name := range c.Fields {
+		if name == "" {
+			return fmt.Errorf("fields[%s]: credential name must be defined", field)
+		}
+		if _, found := names[name]; found {
+			return fmt.Errorf("fields[%s]: duplicate credential name %q", field, name)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+// New returns a new sidecred.Provider for Vault secrets.
+func New(client VaultAPI, options ...option) sidecred.Provider {
+	p := &provider{
+		client:               client,
+		defaultLeaseDuration: 1 * time.Hour,
+	}
+	for _, optionFunc := range options {
+		optionFunc(p)
+	}
+	return p
+}
+
+type option func(*provider)
+
+// WithDefaultLeaseDuration sets the expiration used for secrets that do not have a lease (e.g. KV secrets).
+func WithDefaultLeaseDuration(duration time.Duration) option {
+	return func(p *provider) {
+		p.defaultLeaseDuration = duration
+	}
+}
+
+type provider struct {
+	client               VaultAPI
+	defaultLeaseDuration time.Duration
+}
+
+// Type implements sidecred.Provider.
+func (p *provider) Type() sidecred.ProviderType {
+	return sidecred.Vault
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c RequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+
+	var (
+		secret *vaultapi.Secret
+		err    error
+	)
+	if c.Data != nil {
+		secret, err = p.client.Write(ctx, c.Path, c.Data)
+	} else {
+		secret, err = p.client.Read(ctx, c.Path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("get secret: %s", err)
+	}
+	if secret == nil || len(secret.Data) == 0 {
+		return nil, nil, fmt.Errorf("no secret found at path: %s", c.Path)
+	}
+
+	var metadata *sidecred.Metadata
+	if secret.LeaseID != "" {
+		metadata = &sidecred.Metadata{"lease_id": secret.LeaseID}
+	}
+	credentials, err := p.mapFields(request.Name, &c, secret)
+	if err != nil {
+		// Revoke the lease since the credentials are never written to a store.
+		if secret.LeaseID != "" {
+			if revokeErr := p.client.RevokeLease(ctx, secret.LeaseID); revokeErr != nil {
+				return nil, nil, fmt.Errorf("%s (revoke lease: %s)", err, revokeErr)
+			}
+		}
+		return nil, nil, err
+	}
+	return credentials, metadata, nil
+}
+
+func (p *provider) mapFields(name string, c *RequestConfig, secret *vaultapi.Secret) ([]*sidecred.Credential, error) {
+	duration := p.defaultLeaseDuration
+	if secret.LeaseDuration > 0 {
+		duration = time.Duration(secret.LeaseDuration) * time.Second
+	}
+	expiration := time.Now().Add(duration).UTC()
+
+	fields := c.Fields
+	if len(fields) == 0 {
+		fields = make(map[string]string, len(secret.Data))
+		for field := range secret.Data {
+			fields[field] = name + "-" + field
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for field := range fields {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
+
+	var credentials []*sidecred.Credential
+	for _, field := range keys {
+		v, found := secret.Data[field]
+		if !found {
+			return nil, fmt.Errorf("field %q not found in secret: %s", field, c.Path)
+		}
+		value, err := stringValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %s", field, err)
+		}
+		credentials = append(credentials, &sidecred.Credential{
+			Name:        fields[field],
+			Value:       value,
+			Description: "Vault secret managed by sidecred.",
+			Expiration:  expiration,
+		})
+	}
+	return credentials, nil
+}
+
+// stringValue returns strings as-is, and JSON encodes other values.
+func stringValue(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Destroy implements sidecred.Provider.
+func (p *provider) Destroy(ctx context.Context, resource *sidecred.Resource) error {
+	if resource.Metadata == nil {
+		return nil
+	}
+	leaseID := (*resource.Metadata)["lease_id"]
+	if leaseID == "" {
+		return nil
+	}
+	if err := p.client.RevokeLease(ctx, leaseID); err != nil {
+		if vaultapi.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("revoke lease: %s", err)
+	}
+	return nil
+}
+
+// VaultAPI wraps the Vault API.
+//
+//counterfeiter:generate . VaultAPI
+type VaultAPI interface {
+	Read(ctx context.Context, path string) (*vaultapi.Secret, error)
+	Write(ctx context.Context, path string, data map[string]interface{}) (*vaultapi.Secret, error)
+	RevokeLease(ctx context.Context, leaseID string) error
+}
@@ -0,0 +1,186 @@
+package vault_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	vaultapi "github.com/telia-oss/sidecred/internal/vault"
+	provider "github.com/telia-oss/sidecred/provider/vault"
+	"github.com/telia-oss/sidecred/provider/vault/vaultfakes"
+)
+
+func TestVaultProvider(t *testing.T) {
+	tests := []struct {
+		description         string
+		config              string
+		secret              *vaultapi.Secret
+		expectedCredentials map[string]string
+		expectedMetadata    *sidecred.Metadata
+		expectedDuration    time.Duration
+		expectedReadCalls   int
+		expectedWriteCalls  int
+		expectedRevokeCalls int
+		expectedError       string
+	}{
+		{
+			description: "vault provider works",
+			config:      `{"path":"database/creds/readonly"}`,
+			secret: &vaultapi.Secret{
+				LeaseID:       "database/creds/readonly/abc",
+				LeaseDuration: 3600,
+				Data:          map[string]interface{}{"username": "user", "password": "pass"},
+			},
+			expectedCredentials: map[string]string{
+				"request-name-username": "user",
+				"request-name-password": "pass",
+			},
+			expectedMetadata:  &sidecred.Metadata{"lease_id": "database/creds/readonly/abc"},
+			expectedDuration:  time.Hour,
+			expectedReadCalls: 1,
+		},
+		{
+			description: "maps fields to credential names",
+			config:      `{"path":"aws/sts/deploy","data":{"ttl":"30m"},"fields":{"access_key":"aws-access-key","secret_key":"aws-secret-key"}}`,
+			secret: &vaultapi.Secret{
+				LeaseID:       "aws/sts/deploy/abc",
+				LeaseDuration: 1800,
+				Data:          map[string]interface{}{"access_key": "key", "secret_key": "secret", "security_token": "token"},
+			},
+			expectedCredentials: map[string]string{
+				"aws-access-key": "key",
+				"aws-secret-key": "secret",
+			},
+			expectedMetadata:   &sidecred.Metadata{"lease_id": "aws/sts/deploy/abc"},
+			expectedDuration:   30 * time.Minute,
+			expectedWriteCalls: 1,
+		},
+		{
+			description: "uses the default lease duration for secrets without a lease",
+			config:      `{"path":"secret/static"}`,
+			secret: &vaultapi.Secret{
+				Data: map[string]interface{}{"port": 5432},
+			},
+			expectedCredentials: map[string]string{
+				"request-name-port": "5432",
+			},
+			expectedDuration:  24 * time.Hour,
+			expectedReadCalls: 1,
+		},
+		{
+			description: "revokes the lease if a field is missing",
+			config:      `{"path":"database/creds/readonly","fields":{"user":"database-user"}}`,
+			secret: &vaultapi.Secret{
+				LeaseID:       "database/creds/readonly/abc",
+				LeaseDuration: 3600,
+				Data:          map[string]interface{}{"username": "user", "password": "pass"},
+			},
+			expectedReadCalls:   1,
+			expectedRevokeCalls: 1,
+			expectedError:       `field "user" not found in secret: database/creds/readonly`,
+		},
+		{
+			description:       "fails if the secret does not exist",
+			config:            `{"path":"database/creds/missing"}`,
+			expectedReadCalls: 1,
+			expectedError:     "no secret found at path: database/creds/missing",
+		},
+		{
+			description:   "validates the config",
+			config:        `{"fields":{"username":"database-username"}}`,
+			expectedError: `invalid config: "path" must be defined`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeVaultAPI := &vaultfakes.FakeVaultAPI{}
+			fakeVaultAPI.ReadReturns(tc.secret, nil)
+			fakeVaultAPI.WriteReturns(tc.secret, nil)
+
+			p := provider.New(fakeVaultAPI, provider.WithDefaultLeaseDuration(24*time.Hour))
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.VaultSecret,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			assert.Equal(t, tc.expectedReadCalls, fakeVaultAPI.ReadCallCount(), "read calls")
+			assert.Equal(t, tc.expectedWriteCalls, fakeVaultAPI.WriteCallCount(), "write calls")
+			assert.Equal(t, tc.expectedRevokeCalls, fakeVaultAPI.RevokeLeaseCallCount(), "revoke calls")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, metadata)
+
+			actual := make(map[string]string, len(creds))
+			for _, c := range creds {
+				actual[c.Name] = c.Value
+				assert.WithinDuration(t, time.Now().Add(tc.expectedDuration), c.Expiration, time.Minute)
+			}
+			assert.Equal(t, tc.expectedCredentials, actual)
+		})
+	}
+}
+
+func TestVaultProviderDestroy(t *testing.T) {
+	tests := []struct {
+		description         string
+		metadata            *sidecred.Metadata
+		revokeError         error
+		expectedRevokeCalls int
+		expectedError       string
+	}{
+		{
+			description:         "revokes the lease",
+			metadata:            &sidecred.Metadata{"lease_id": "database/creds/readonly/abc"},
+			expectedRevokeCalls: 1,
+		},
+		{
+			description: "does nothing for secrets without a lease",
+		},
+		{
+			description:         "ignores leases that do not exist",
+			metadata:            &sidecred.Metadata{"lease_id": "database/creds/readonly/abc"},
+			revokeError:         &vaultapi.ResponseError{StatusCode: 404},
+			expectedRevokeCalls: 1,
+		},
+		{
+			description:         "propagates errors",
+			metadata:            &sidecred.Metadata{"lease_id": "database/creds/readonly/abc"},
+			revokeError:         errors.New("failure"),
+			expectedRevokeCalls: 1,
+			expectedError:       "revoke lease: failure",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeVaultAPI := &vaultfakes.FakeVaultAPI{}
+			fakeVaultAPI.RevokeLeaseReturns(tc.revokeError)
+
+			err := provider.New(fakeVaultAPI).Destroy(context.TODO(), &sidecred.Resource{
+				Type:     sidecred.VaultSecret,
+				ID:       "request-name",
+				Metadata: tc.metadata,
+			})
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRevokeCalls, fakeVaultAPI.RevokeLeaseCallCount())
+			if tc.expectedRevokeCalls > 0 {
+				_, leaseID := fakeVaultAPI.RevokeLeaseArgsForCall(0)
+				assert.Equal(t, (*tc.metadata)["lease_id"], leaseID)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package vaultfakes
+
+import (
+	"context"
+	"sync"
+
+	vaulta "github.com/telia-oss/sidecred/internal/vault"
+	"github.com/telia-oss/sidecred/provider/vault"
+)
+
+type FakeVaultAPI struct {
+	ReadStub        func(context.Context, string) (*vaulta.Secret, error)
+	readMutex       sync.RWMutex
+	readArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	readReturns struct {
+		result1 *vaulta.Secret
+		result2 error
+	}
+	readReturnsOnCall map[int]struct {
+		result1 *vaulta.Secret
+		result2 error
+	}
+	RevokeLeaseStub        func(context.Context, string) error
+	revokeLeaseMutex       sync.RWMutex
+	revokeLeaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	revokeLeaseReturns struct {
+		result1 error
+	}
+	revokeLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteStub        func(context.Context, string, map[string]interface{}) (*vaulta.Secret, error)
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]interface{}
+	}
+	writeReturns struct {
+		result1 *vaulta.Secret
+		result2 error
+	}
+	writeReturnsOnCall map[int]struct {
+		result1 *vaulta.Secret
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeVaultAPI) Read(arg1 context.Context, arg2 string) (*vaulta.Secret, error) {
+	fake.readMutex.Lock()
+	ret, specificReturn := fake.readReturnsOnCall[len(fake.readArgsForCall)]
+	fake.readArgsForCall = append(fake.readArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReadStub
+	fakeReturns := fake.readReturns
+	fake.recordInvocation("Read", []interface{}{arg1, arg2})
+	fake.readMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVaultAPI) ReadCallCount() int {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	return len(fake.readArgsForCall)
+}
+
+func (fake *FakeVaultAPI) ReadCalls(stub func(context.Context, string) (*vaulta.Secret, error)) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = stub
+}
+
+func (fake *FakeVaultAPI) ReadArgsForCall(i int) (context.Context, string) {
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	argsForCall := fake.readArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVaultAPI) ReadReturns(result1 *vaulta.Secret, result2 error) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = nil
+	fake.readReturns = struct {
+		result1 *vaulta.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVaultAPI) ReadReturnsOnCall(i int, result1 *vaulta.Secret, result2 error) {
+	fake.readMutex.Lock()
+	defer fake.readMutex.Unlock()
+	fake.ReadStub = nil
+	if fake.readReturnsOnCall == nil {
+		fake.readReturnsOnCall = make(map[int]struct {
+			result1 *vaulta.Secret
+			result2 error
+		})
+	}
+	fake.readReturnsOnCall[i] = struct {
+		result1 *vaulta.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVaultAPI) RevokeLease(arg1 context.Context, arg2 string) error {
+	fake.revokeLeaseMutex.Lock()
+	ret, specificReturn := fake.revokeLeaseReturnsOnCall[len(fake.revokeLeaseArgsForCall)]
+	fake.revokeLeaseArgsForCall = append(fake.revokeLeaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RevokeLeaseStub
+	fakeReturns := fake.revokeLeaseReturns
+	fake.recordInvocation("RevokeLease", []interface{}{arg1, arg2})
+	fake.revokeLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeVaultAPI) RevokeLeaseCallCount() int {
+	fake.revokeLeaseMutex.RLock()
+	defer fake.revokeLeaseMutex.RUnlock()
+	return len(fake.revokeLeaseArgsForCall)
+}
+
+func (fake *FakeVaultAPI) RevokeLeaseCalls(stub func(context.Context, string) error) {
+	fake.revokeLeaseMutex.Lock()
+	defer fake.revokeLeaseMutex.Unlock()
+	fake.RevokeLeaseStub = stub
+}
+
+func (fake *FakeVaultAPI) RevokeLeaseArgsForCall(i int) (context.Context, string) {
+	fake.revokeLeaseMutex.RLock()
+	defer fake.revokeLeaseMutex.RUnlock()
+	argsForCall := fake.revokeLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVaultAPI) RevokeLeaseReturns(result1 error) {
+	fake.revokeLeaseMutex.Lock()
+	defer fake.revokeLeaseMutex.Unlock()
+	fake.RevokeLeaseStub = nil
+	fake.revokeLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVaultAPI) RevokeLeaseReturnsOnCall(i int, result1 error) {
+	fake.revokeLeaseMutex.Lock()
+	defer fake.revokeLeaseMutex.Unlock()
+	fake.RevokeLeaseStub = nil
+	if fake.revokeLeaseReturnsOnCall == nil {
+		fake.revokeLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVaultAPI) Write(arg1 context.Context, arg2 string, arg3 map[string]interface{}) (*vaulta.Secret, error) {
+	fake.writeMutex.Lock()
+	ret, specificReturn := fake.writeReturnsOnCall[len(fake.writeArgsForCall)]
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 map[string]interface{}
+	}{arg1, arg2, arg3})
+	stub := fake.WriteStub
+	fakeReturns := fake.writeReturns
+	fake.recordInvocation("Write", []interface{}{arg1, arg2, arg3})
+	fake.writeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeVaultAPI) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *FakeVaultAPI) WriteCalls(stub func(context.Context, string, map[string]interface{}) (*vaulta.Secret, error)) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = stub
+}
+
+func (fake *FakeVaultAPI) WriteArgsForCall(i int) (context.Context, string, map[string]interface{}) {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	argsForCall := fake.writeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeVaultAPI) WriteReturns(result1 *vaulta.Secret, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 *vaulta.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVaultAPI) WriteReturnsOnCall(i int, result1 *vaulta.Secret, result2 error) {
+	fake.writeMutex.Lock()
+	defer fake.writeMutex.Unlock()
+	fake.WriteStub = nil
+	if fake.writeReturnsOnCall == nil {
+		fake.writeReturnsOnCall = make(map[int]struct {
+			result1 *vaulta.Secret
+			result2 error
+		})
+	}
+	fake.writeReturnsOnCall[i] = struct {
+		result1 *vaulta.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeVaultAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.readMutex.RLock()
+	defer fake.readMutex.RUnlock()
+	fake.revokeLeaseMutex.RLock()
+	defer fake.revokeLeaseMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeVaultAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ vault.VaultAPI = new(FakeVaultAPI)
@@ -0,0 +1,340 @@
+// Package x509 implements a sidecred.Provider for X.509 certificates issued by a local certificate authority (CA).
+package x509
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	cryptox509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/telia-oss/sidecred"
+)
+
+var _ sidecred.Validatable = &RequestConfig{}
+
+// Enumeration of supported key types.
+const (
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeRSA     = "rsa"
+	KeyTypeED25519 = "ed25519"
+)
+
+var keyUsages = map[string]cryptox509.KeyUsage{
+	"digital_signature":  cryptox509.KeyUsageDigitalSignature,
+	"content_commitment": cryptox509.KeyUsageContentCommitment,
+	"key_encipherment":   cryptox509.KeyUsageKeyEncipherment,
+	"data_encipherment":  cryptox509.KeyUsageDataEncipherment,
+	"key_agreement":      cryptox509.KeyUsageKeyAgreement,
+}
+
+var extKeyUsages = map[string]cryptox509.ExtKeyUsage{
+	"client_auth":      cryptox509.ExtKeyUsageClientAuth,
+	"server_auth":      cryptox509.ExtKeyUsageServerAuth,
+	"code_signing":     cryptox509.ExtKeyUsageCodeSigning,
+	"email_protection": cryptox509.ExtKeyUsageEmailProtection,
+}
+
+// RequestConfig ...
+// Key usage defaults to "digital_signature" (and "key_encipherment" for RSA keys), and the
+// extended key usage defaults to "client_auth".
+type RequestConfig struct {
+	CommonName     string             `json:"common_name"`
+	DNSNames       []string           `json:"dns_names,omitempty"`
+	IPAddresses    []string           `json:"ip_addresses,omitempty"`
+	URIs           []string           `json:"uris,omitempty"`
+	EmailAddresses []string           `json:"email_addresses,omitempty"`
+	KeyType        string             `json:"key_type,omitempty"`
+	KeyUsage       []string           `json:"key_usage,omitempty"`
+	ExtKeyUsage    []string           `json:"ext_key_usage,omitempty"`
+	TTL            *sidecred.Duration `json:"ttl,omitempty"`
+}
+
+// Validate implements sidecred.Validatable.
+func (c *RequestConfig) Validate() error {
+	if c.CommonName == "" {
+		return fmt.Errorf("%q must be defined", "common_name")
+	}
+	for i, ip := range c.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("ip_addresses[%d]: invalid ip address %q", i, ip)
+		}
+	}
+	for i, u := range c.URIs {
+		if _, err := url.Parse(u); err != nil {
+			return fmt.Errorf("uris[%d]: %s", i, err)
+		}
+	}
+	switch c.KeyType {
+	case "", KeyTypeECDSA, KeyTypeRSA, KeyTypeED25519:
+	default:
+		return fmt.Errorf("unknown key type %q", c.KeyType)
+	}
+	for _, u := range c.KeyUsage {
+		if _, ok := keyUsages[u]; !ok {
+			return fmt.Errorf("unknown key usage %q", u)
+		}
+	}
+	for _, u := range c.ExtKeyUsage {
+		if _, ok := extKeyUsages[u]; !ok {
+			return fmt.Errorf("unknown extended key usage %q", u)
+		}
+	}
+	if c.TTL != nil && c.TTL.Duration <= 0 {
+		return fmt.Errorf("%q must be greater than zero", "ttl")
+	}
+	return nil
+}
+
+// CA is the certificate authority used to issue certificates.
+type CA struct {
+	// Certificate for the CA.
+	Certificate *cryptox509.Certificate
+	// Chain is the certificate chain (starting with the CA certificate) that is returned with the issued certificates.
+	Chain []*cryptox509.Certificate
+	// Key is the private key for the CA.
+	Key crypto.Signer
+}
+
+// ParseCA parses a PEM encoded CA certificate (optionally followed by the certificates in its chain) and private key.
+// The private key must match the public key of the CA certificate.
+func ParseCA(certificate, privateKey []byte) (*CA, error) {
+	var chain []*cryptox509.Certificate
+	for rest := certificate; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := cryptox509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate: %s", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("no certificates found")
+	}
+
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("no private key found")
+	}
+	key, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %s", err)
+	}
+	if !chain[0].IsCA {
+		return nil, errors.New("certificate is not a CA")
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(chain[0].PublicKey) {
+		return nil, errors.New("private key does not match the certificate")
+	}
+	return &CA{Certificate: chain[0], Chain: chain, Key: key}, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return cryptox509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return cryptox509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := cryptox509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// New returns a new sidecred.Provider for X.509 certificates issued by the given CA.
+func New(ca *CA, options ...option) sidecred.Provider {
+	p := &provider{
+		ca:        ca,
+		ttl:       24 * time.Hour,
+		maxTTL:    30 * 24 * time.Hour,
+		clockSkew: 5 * time.Minute,
+	}
+	for _, optionFunc := range options {
+		optionFunc(p)
+	}
+	return p
+}
+
+type option func(*provider)
+
+// WithCertificateTTL overrides the default TTL of issued certificates.
+func WithCertificateTTL(ttl time.Duration) option {
+	return func(p *provider) {
+		p.ttl = ttl
+	}
+}
+
+// WithMaxCertificateTTL sets the maximum TTL that can be requested for certificates.
+func WithMaxCertificateTTL(ttl time.Duration) option {
+	return func(p *provider) {
+		p.maxTTL = ttl
+	}
+}
+
+type provider struct {
+	ca        *CA
+	ttl       time.Duration
+	maxTTL    time.Duration
+	clockSkew time.Duration
+}
+
+// Type implements sidecred.Provider.
+func (p *provider) Type() sidecred.ProviderType {
+	return sidecred.X509
+}
+
+// Create implements sidecred.Provider.
+func (p *provider) Create(ctx context.Context, request *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
+	var c RequestConfig
+	if err := request.UnmarshalConfig(&c); err != nil {
+		return nil, nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %s", err)
+	}
+	ttl := p.ttl
+	if c.TTL != nil {
+		ttl = c.TTL.Duration
+	}
+	if ttl > p.maxTTL {
+		return nil, nil, fmt.Errorf("requested ttl (%s) exceeds the maximum ttl (%s)", ttl, p.maxTTL)
+	}
+
+	key, err := generateKey(c.KeyType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key: %s", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate serial: %s", err)
+	}
+
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	// Certificates cannot outlive the CA, and silently issuing a shorter lived certificate would hide that the CA is expiring.
+	if notAfter.After(p.ca.Certificate.NotAfter) {
+		return nil, nil, fmt.Errorf("requested ttl (%s) exceeds the remaining lifetime of the ca (expires %s)", ttl, p.ca.Certificate.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	template := &cryptox509.Certificate{
+		SerialNumber:   serial,
+		Subject:        pkix.Name{CommonName: c.CommonName},
+		DNSNames:       c.DNSNames,
+		EmailAddresses: c.EmailAddresses,
+		// Backdate the certificate slightly to allow for clock skew.
+		NotBefore:             now.Add(-p.clockSkew),
+		NotAfter:              notAfter,
+		KeyUsage:              cryptox509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []cryptox509.ExtKeyUsage{cryptox509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	if c.KeyType == KeyTypeRSA {
+		template.KeyUsage |= cryptox509.KeyUsageKeyEncipherment
+	}
+	if len(c.KeyUsage) > 0 {
+		template.KeyUsage = 0
+		for _, u := range c.KeyUsage {
+			template.KeyUsage |= keyUsages[u]
+		}
+	}
+	if len(c.ExtKeyUsage) > 0 {
+		template.ExtKeyUsage = nil
+		for _, u := range c.ExtKeyUsage {
+			template.ExtKeyUsage = append(template.ExtKeyUsage, extKeyUsages[u])
+		}
+	}
+	for _, ip := range c.IPAddresses {
+		template.IPAddresses = append(template.IPAddresses, net.ParseIP(ip))
+	}
+	for _, u := range c.URIs {
+		uri, _ := url.Parse(u) // Validated above.
+		template.URIs = append(template.URIs, uri)
+	}
+
+	der, err := cryptox509.CreateCertificate(rand.Reader, template, p.ca.Certificate, key.Public(), p.ca.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create certificate: %s", err)
+	}
+	cert, err := cryptox509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse certificate: %s", err)
+	}
+	privateKey, err := cryptox509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal private key: %s", err)
+	}
+
+	var chain bytes.Buffer
+	for _, chainCert := range p.ca.Chain {
+		if err := pem.Encode(&chain, &pem.Block{Type: "CERTIFICATE", Bytes: chainCert.Raw}); err != nil {
+			return nil, nil, fmt.Errorf("encode certificate chain: %s", err)
+		}
+	}
+
+	expiration := cert.NotAfter.UTC()
+	metadata := &sidecred.Metadata{"serial": cert.SerialNumber.String()}
+	return []*sidecred.Credential{
+		{
+			Name:        request.Name + "-private-key",
+			Value:       string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey})),
+			Description: "X.509 private key managed by sidecred.",
+			Expiration:  expiration,
+		},
+		{
+			Name:        request.Name + "-certificate",
+			Value:       string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			Description: "X.509 certificate managed by sidecred.",
+			Expiration:  expiration,
+		},
+		{
+			Name:        request.Name + "-ca-chain",
+			Value:       chain.String(),
+			Description: "X.509 CA certificate chain managed by sidecred.",
+			Expiration:  expiration,
+		},
+	}, metadata, nil
+}
+
+// Destroy implements sidecred.Provider.
+func (p *provider) Destroy(_ context.Context, _ *sidecred.Resource) error {
+	// The local CA does not publish a revocation list, so we rely on the certificates
+	// expiring instead.
+	return nil
+}
+
+func generateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case KeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyTypeED25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+}
@@ -0,0 +1,214 @@
+package x509_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	cryptox509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	provider "github.com/telia-oss/sidecred/provider/x509"
+)
+
+func TestX509Provider(t *testing.T) {
+	tests := []struct {
+		description         string
+		config              string
+		caTTL               time.Duration
+		expectedDNSNames    []string
+		expectedKeyUsage    cryptox509.KeyUsage
+		expectedExtKeyUsage []cryptox509.ExtKeyUsage
+		expectedTTL         time.Duration
+		expectedError       string
+	}{
+		{
+			description:         "x509 provider works",
+			config:              `{"common_name":"service.example.com"}`,
+			caTTL:               365 * 24 * time.Hour,
+			expectedKeyUsage:    cryptox509.KeyUsageDigitalSignature,
+			expectedExtKeyUsage: []cryptox509.ExtKeyUsage{cryptox509.ExtKeyUsageClientAuth},
+			expectedTTL:         24 * time.Hour,
+		},
+		{
+			description:         "supports sans, key usage and ttl",
+			config:              `{"common_name":"service","dns_names":["service.example.com"],"ip_addresses":["10.0.0.1"],"key_type":"rsa","ext_key_usage":["server_auth","client_auth"],"ttl":"72h"}`,
+			caTTL:               365 * 24 * time.Hour,
+			expectedDNSNames:    []string{"service.example.com"},
+			expectedKeyUsage:    cryptox509.KeyUsageDigitalSignature | cryptox509.KeyUsageKeyEncipherment,
+			expectedExtKeyUsage: []cryptox509.ExtKeyUsage{cryptox509.ExtKeyUsageServerAuth, cryptox509.ExtKeyUsageClientAuth},
+			expectedTTL:         72 * time.Hour,
+		},
+		{
+			description:         "supports ed25519 keys",
+			config:              `{"common_name":"service.example.com","key_type":"ed25519","ttl":"72h"}`,
+			caTTL:               365 * 24 * time.Hour,
+			expectedKeyUsage:    cryptox509.KeyUsageDigitalSignature,
+			expectedExtKeyUsage: []cryptox509.ExtKeyUsage{cryptox509.ExtKeyUsageClientAuth},
+			expectedTTL:         72 * time.Hour,
+		},
+		{
+			description:   "certificates do not outlive the ca",
+			config:        `{"common_name":"service.example.com","ttl":"72h"}`,
+			caTTL:         48 * time.Hour,
+			expectedError: "requested ttl (72h0m0s) exceeds the remaining lifetime of the ca",
+		},
+		{
+			description:   "enforces the maximum ttl",
+			config:        `{"common_name":"service.example.com","ttl":"8760h"}`,
+			caTTL:         365 * 24 * time.Hour,
+			expectedError: "requested ttl (8760h0m0s) exceeds the maximum ttl (720h0m0s)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			ca := newTestCA(t, tc.caTTL)
+			p := provider.New(ca)
+
+			creds, metadata, err := p.Create(context.TODO(), &sidecred.CredentialRequest{
+				Type:   sidecred.X509Certificate,
+				Name:   "request-name",
+				Config: []byte(tc.config),
+			})
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, creds, 3)
+			assert.Equal(t, "request-name-private-key", creds[0].Name)
+			assert.Equal(t, "request-name-certificate", creds[1].Name)
+			assert.Equal(t, "request-name-ca-chain", creds[2].Name)
+
+			block, _ := pem.Decode([]byte(creds[1].Value))
+			require.NotNil(t, block)
+			cert, err := cryptox509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+
+			block, _ = pem.Decode([]byte(creds[0].Value))
+			require.NotNil(t, block)
+			key, err := cryptox509.ParsePKCS8PrivateKey(block.Bytes)
+			require.NoError(t, err)
+			assert.Equal(t, cert.PublicKey, key.(crypto.Signer).Public())
+
+			roots := cryptox509.NewCertPool()
+			require.True(t, roots.AppendCertsFromPEM([]byte(creds[2].Value)))
+			_, err = cert.Verify(cryptox509.VerifyOptions{Roots: roots, KeyUsages: []cryptox509.ExtKeyUsage{cryptox509.ExtKeyUsageAny}})
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedDNSNames, cert.DNSNames)
+			assert.Equal(t, tc.expectedKeyUsage, cert.KeyUsage)
+			assert.Equal(t, tc.expectedExtKeyUsage, cert.ExtKeyUsage)
+			assert.WithinDuration(t, time.Now().Add(tc.expectedTTL), cert.NotAfter, time.Minute)
+			for _, c := range creds {
+				assert.Equal(t, cert.NotAfter.UTC(), c.Expiration)
+			}
+			assert.Equal(t, cert.SerialNumber.String(), (*metadata)["serial"])
+		})
+	}
+}
+
+func TestParseCA(t *testing.T) {
+	ca := newTestCA(t, time.Hour)
+
+	key, err := cryptox509.MarshalPKCS8PrivateKey(ca.Key)
+	require.NoError(t, err)
+
+	parsed, err := provider.ParseCA(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ca.Certificate.Raw, parsed.Certificate.Raw)
+	assert.Len(t, parsed.Chain, 1)
+
+	_, err = provider.ParseCA(nil, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	assert.EqualError(t, err, "no certificates found")
+
+	other, err := cryptox509.MarshalPKCS8PrivateKey(newTestCA(t, time.Hour).Key)
+	require.NoError(t, err)
+
+	_, err = provider.ParseCA(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: other}),
+	)
+	assert.EqualError(t, err, "private key does not match the certificate")
+}
+
+func TestRequestConfig(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        string
+		expectedError string
+	}{
+		{
+			description: "valid config",
+			config:      `{"common_name":"service","key_usage":["digital_signature"],"ext_key_usage":["server_auth"]}`,
+		},
+		{
+			description:   "requires a common name",
+			config:        `{}`,
+			expectedError: `"common_name" must be defined`,
+		},
+		{
+			description:   "invalid ip address",
+			config:        `{"common_name":"service","ip_addresses":["10.0.0"]}`,
+			expectedError: `ip_addresses[0]: invalid ip address "10.0.0"`,
+		},
+		{
+			description:   "unknown key usage",
+			config:        `{"common_name":"service","key_usage":["cert_sign"]}`,
+			expectedError: `unknown key usage "cert_sign"`,
+		},
+		{
+			description:   "unknown extended key usage",
+			config:        `{"common_name":"service","ext_key_usage":["any"]}`,
+			expectedError: `unknown extended key usage "any"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			c := &provider.RequestConfig{}
+			require.NoError(t, sidecred.UnmarshalConfig([]byte(tc.config), c))
+			err := c.Validate()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func newTestCA(t *testing.T, ttl time.Duration) *provider.CA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &cryptox509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sidecred test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(ttl),
+		KeyUsage:              cryptox509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := cryptox509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := cryptox509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &provider.CA{Certificate: cert, Chain: []*cryptox509.Certificate{cert}, Key: key}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -169,7 +170,14 @@ const (
 	AWSSTS                 CredentialType = "aws:sts"
 	GithubDeployKey        CredentialType = "github:deploy-key"
 	GithubAccessToken      CredentialType = "github:access-token"
+	GithubWebhookSecret    CredentialType = "github:webhook-secret"
 	ArtifactoryAccessToken CredentialType = "artifactory:access-token"
+	SSHCertificate         CredentialType = "ssh:certificate"
+	X509Certificate        CredentialType = "x509:certificate"
+	VaultSecret            CredentialType = "vault:secret"
+	DatabasePassword       CredentialType = "database:password"
+	DockerConfig           CredentialType = "docker:config"
+	JWTToken               CredentialType = "jwt:token"
 )
 
 // Provider returns the sidecred.ProviderType for the credential.
@@ -179,10 +187,22 @@ func (c CredentialType) Provider() ProviderType {
 		return Random
 	case AWSSTS:
 		return AWS
-	case GithubDeployKey, GithubAccessToken:
+	case GithubDeployKey, GithubAccessToken, GithubWebhookSecret:
 		return Github
 	case ArtifactoryAccessToken:
 		return Artifactory
+	case SSHCertificate:
+		return SSH
+	case X509Certificate:
+		return X509
+	case VaultSecret:
+		return Vault
+	case DatabasePassword:
+		return Database
+	case DockerConfig:
+		return Docker
+	case JWTToken:
+		return JWT
 	}
 	return ProviderType(c)
 }
@@ -193,6 +213,12 @@ const (
 	AWS         ProviderType = "aws"
 	Github      ProviderType = "github"
 	Artifactory ProviderType = "artifactory"
+	SSH         ProviderType = "ssh"
+	X509        ProviderType = "x509"
+	Vault       ProviderType = "vault"
+	Database    ProviderType = "database"
+	Docker      ProviderType = "docker"
+	JWT         ProviderType = "jwt"
 )
 
 // ProviderType ...
@@ -214,6 +240,52 @@ type Provider interface {
 	Destroy(ctx context.Context, resource *Resource) error
 }
 
+// RequestValidator can optionally be implemented by a sidecred.Provider in order to validate
+// requests using information that is only available to the provider (e.g. permissions).
+// Requests are validated by Sidecred.Validate, and before new credentials are created (where
+// invalid requests are skipped). Invalid requests must be reported using an InvalidRequestError,
+// since other errors (e.g. failing to look up permissions) are treated as transient and fail
+// the run.
+type RequestValidator interface {
+	ValidateRequest(ctx context.Context, request *CredentialRequest) error
+}
+
+// InvalidRequestError is returned by a sidecred.RequestValidator when a request is invalid.
+type InvalidRequestError struct {
+	Err error
+}
+
+// Error implements error.
+func (e *InvalidRequestError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *InvalidRequestError) Unwrap() error {
+	return e.Err
+}
+
+// RequestLinter can optionally be implemented by a sidecred.Provider in order to warn about requests that
+// are valid, but could be improved (e.g. requesting more access than needed). Requests are only linted by
+// Sidecred.Validate, to avoid repeating the same warnings every time credentials are rotated.
+type RequestLinter interface {
+	LintRequest(request *CredentialRequest) []string
+}
+
+// Reconciler can optionally be implemented by a sidecred.Provider in order to clean up resources that were created
+// by sidecred for a request, but which are not tracked in state (e.g. due to lost state). Reconcile is called with all
+// the resources in state for the provider, after new credentials have been written to the secret store.
+type Reconciler interface {
+	Reconcile(ctx context.Context, request *CredentialRequest, resources []*Resource) error
+}
+
+// DestroyDeferrer can optionally be implemented by a sidecred.Provider in order to keep deposed resources
+// (i.e. the previous generation of credentials) until they expire, instead of destroying them as soon as they
+// have been replaced. Resources are still destroyed immediately when they are no longer requested.
+type DestroyDeferrer interface {
+	DeferDestroy(resource *Resource) bool
+}
+
 // Metadata allows providers to pass additional information to be
 // stored in the sidecred.ResourceState after successfully creating
 // credentials.
@@ -236,11 +308,18 @@ type Credential struct {
 
 // Enumeration of known backends.
 const (
-	Inprocess               StoreType = "inprocess"
-	SecretsManager          StoreType = "secretsmanager"
-	SSM                     StoreType = "ssm"
-	GithubSecrets           StoreType = "github"
-	GithubDependabotSecrets StoreType = "github:dependabot"
+	Inprocess                 StoreType = "inprocess"
+	SecretsManager            StoreType = "secretsmanager"
+	SSM                       StoreType = "ssm"
+	GithubSecrets             StoreType = "github"
+	GithubDependabotSecrets   StoreType = "github:dependabot"
+	GithubEnvironmentSecrets  StoreType = "github:environment"
+	GithubOrganizationSecrets StoreType = "github:organization"
+	GithubCodespacesSecrets   StoreType = "github:codespaces"
+	Kubernetes                StoreType = "kubernetes"
+	VaultSecrets              StoreType = "vault"
+	GitlabVariables           StoreType = "gitlab"
+	File                      StoreType = "file"
 )
 
 // StoreType ...
@@ -277,6 +356,31 @@ type SecretStore interface {
 	Delete(ctx context.Context, path string, config json.RawMessage) error
 }
 
+// SecretBundler can optionally be implemented by a sidecred.SecretStore in order to write all the
+// credentials returned for a request as a single secret (e.g. a JSON document), instead of one secret
+// per credential. WriteBundle is used instead of Write when Bundle returns true for the store config.
+type SecretBundler interface {
+	// Bundle returns true if credentials should be bundled for the store config.
+	Bundle(config json.RawMessage) bool
+
+	// WriteBundle writes the credentials for the named request as a single secret.
+	WriteBundle(ctx context.Context, namespace, name string, secrets []*Credential, config json.RawMessage) (string, error)
+}
+
+// SecretMetadata describes a secret in a store, without the value.
+type SecretMetadata struct {
+	// UpdatedAt is the time the secret was last written.
+	UpdatedAt time.Time
+}
+
+// SecretMetadataReader can optionally be implemented by a sidecred.SecretStore which cannot read back the
+// values of secrets (e.g. Github), so that callers can check whether a secret exists and when it was last
+// updated (e.g. to detect drift from the state) instead of comparing values.
+type SecretMetadataReader interface {
+	// ReadMetadata returns the metadata of the specified secret by reference, and whether the secret exists.
+	ReadMetadata(ctx context.Context, path string, config json.RawMessage) (*SecretMetadata, bool, error)
+}
+
 // BuildSecretTemplate is a convenience function for building secret templates.
 func BuildSecretTemplate(secretTemplate, namespace, name string) (string, error) {
 	t, err := template.New("path").Option("missingkey=error").Parse(secretTemplate)
@@ -322,15 +426,64 @@ type Sidecred struct {
 	rotationWindow time.Duration
 }
 
-// Process a single sidecred.Request.
+// Validate the config, and the requests using the providers that implement sidecred.RequestValidator. Warnings
+// are returned for requests that are valid, but could be improved (see sidecred.RequestLinter).
+func (s *Sidecred) Validate(ctx context.Context, config Config) ([]string, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, request := range config.Requests() {
+		for _, r := range request.Credentials {
+			p, ok := s.providers[r.Type.Provider()]
+			if !ok {
+				continue
+			}
+			if v, ok := p.(RequestValidator); ok {
+				if err := v.ValidateRequest(ctx, r); err != nil {
+					return warnings, fmt.Errorf("%s request %q: %s", r.Type, r.Name, err)
+				}
+			}
+			if l, ok := p.(RequestLinter); ok {
+				for _, w := range l.LintRequest(r) {
+					warnings = append(warnings, fmt.Sprintf("%s request %q: %s", r.Type, r.Name, w))
+				}
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// reconcile the resources for a request if supported by the provider. This should only be done after the
+// credentials have been written to the store, so that existing credentials remain usable if the write fails.
+func (s *Sidecred) reconcile(ctx context.Context, p Provider, request *CredentialRequest, state *State) {
+	r, ok := p.(Reconciler)
+	if !ok {
+		return
+	}
+	var resources []*Resource
+	if ps, ok := state.getProviderState(p.Type()); ok {
+		resources = ps.Resources
+	}
+	if err := r.Reconcile(ctx, request, resources); err != nil {
+		eventctx.GetLogger(ctx).Warn("reconcile resources", zap.String("name", request.Name), zap.Error(err))
+	}
+}
+
+// Process a single sidecred.Request. If a request cannot be validated due to a transient error, the
+// request is skipped and an error is returned after the remaining requests have been processed. The
+// state is still updated in this case, and should be saved.
 func (s *Sidecred) Process(ctx context.Context, config Config, state *State) error {
 	log := eventctx.GetLogger(ctx)
 	log.Info("starting sidecred", zap.Int("requests", len(config.Requests())))
+	ctx = eventctx.SetNamespace(ctx, config.Namespace())
 
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid config: %s", err)
 	}
 
+	var failed []string
+
 RequestLoop:
 	for _, request := range config.Requests() {
 		var storeConfig *StoreConfig
@@ -370,6 +523,19 @@ RequestLoop:
 				}
 			}
 
+			if v, ok := p.(RequestValidator); ok {
+				if err := v.ValidateRequest(ctx, r); err != nil {
+					var invalid *InvalidRequestError
+					if errors.As(err, &invalid) {
+						log.Error("invalid request", zap.Error(err))
+						continue CredentialLoop
+					}
+					log.Error("failed to validate request", zap.Error(err))
+					failed = append(failed, r.Name)
+					continue CredentialLoop
+				}
+			}
+
 			creds, metadata, err := p.Create(ctx, r)
 			if err != nil {
 				log.Error("failed to provide credentials", zap.Error(err))
@@ -382,6 +548,26 @@ RequestLoop:
 			state.AddResource(newResource(r, storeConfig.Alias(), creds[0].Expiration, metadata))
 			log.Info("created new credentials", zap.Int("count", len(creds)))
 
+			if b, ok := store.(SecretBundler); ok && b.Bundle(storeConfig.Config) {
+				path, err := b.WriteBundle(ctx, config.Namespace(), r.Name, creds, storeConfig.Config)
+				if err != nil {
+					log.Error("store credentials", zap.String("name", r.Name), zap.Error(err))
+					continue CredentialLoop
+				}
+				expiration := creds[0].Expiration
+				for _, c := range creds[1:] {
+					if c.Expiration.Before(expiration) {
+						expiration = c.Expiration
+					}
+				}
+				state.AddSecret(storeConfig, newSecret(r.Name, path, expiration))
+				state.DeposeSecrets(storeConfig, r.Name, []string{path})
+				s.reconcile(ctx, p, r, state)
+				log.Info("done processing", zap.String("path", path))
+				continue CredentialLoop
+			}
+
+			var written []string
 			for _, c := range creds {
 				log.Debug("start creds for-loop")
 				path, err := store.Write(ctx, config.Namespace(), c, storeConfig.Config)
@@ -392,6 +578,11 @@ RequestLoop:
 				log.Debug("wrote to store", zap.String("name", c.Name))
 				state.AddSecret(storeConfig, newSecret(r.Name, path, c.Expiration))
 				log.Debug("stored credential", zap.String("path", path))
+				written = append(written, path)
+			}
+			if len(written) == len(creds) {
+				state.DeposeSecrets(storeConfig, r.Name, written)
+				s.reconcile(ctx, p, r, state)
 			}
 			log.Info("done processing")
 		}
@@ -414,6 +605,10 @@ RequestLoop:
 				zap.String("type", string(ps.Type)),
 				zap.String("id", resource.ID),
 			)
+			if d, ok := provider.(DestroyDeferrer); ok && resource.InUse && resource.Expiration.After(time.Now()) && d.DeferDestroy(resource) {
+				log.Debug("deferring destroy of deposed resource")
+				continue
+			}
 			log.Info("destroying expired resource")
 			if err := provider.Destroy(ctx, resource); err != nil {
 				log.Error("destroy resource", zap.Error(err))
@@ -439,5 +634,8 @@ RequestLoop:
 			state.RemoveSecret(ss.StoreConfig, secret)
 		}
 	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to validate requests: %s", strings.Join(failed, ", "))
+	}
 	return nil
 }
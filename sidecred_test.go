@@ -2,22 +2,29 @@ package sidecred_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-github/v45/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/telia-oss/githubapp"
 	"github.com/telia-oss/sidecred"
 	"github.com/telia-oss/sidecred/config"
 	"github.com/telia-oss/sidecred/eventctx"
+	githubprovider "github.com/telia-oss/sidecred/provider/github"
+	"github.com/telia-oss/sidecred/provider/github/githubfakes"
 	"github.com/telia-oss/sidecred/store/inprocess"
 )
 
 var (
-	testStateID = "fake.state.id"
-	testTime    = time.Now().Add(1 * time.Hour)
+	testStateID       = "fake.state.id"
+	testRequestConfig = []byte(`{"length":16}`)
+	testTime          = time.Now().Add(1 * time.Hour)
 )
 
 func TestProcess(t *testing.T) {
@@ -25,10 +32,12 @@ func TestProcess(t *testing.T) {
 		description          string
 		config               string
 		resources            []*sidecred.Resource
+		validateError        error
 		expectedSecrets      map[string]string
 		expectedResources    []*sidecred.Resource
 		expectedCreateCalls  int
 		expectedDestroyCalls int
+		expectedError        string
 	}{
 		{
 			description: "sidecred works",
@@ -45,6 +54,8 @@ requests:
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			expectedSecrets: map[string]string{
 				"team-name.fake-credential": "fake-value",
@@ -54,6 +65,7 @@ requests:
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls: 1,
@@ -73,12 +85,15 @@ requests:
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			resources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 			}},
 			expectedSecrets: map[string]string{},
 			expectedResources: []*sidecred.Resource{{
@@ -86,6 +101,7 @@ requests:
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls: 0,
@@ -105,18 +121,22 @@ requests:
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			resources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: time.Now().Add(3 * time.Minute),
+				Config:     testRequestConfig,
 			}},
 			expectedResources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls:  1,
@@ -138,18 +158,22 @@ requests:
   - type: random
     rotation_window: 30m
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			resources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: time.Now().Add(29 * time.Minute),
+				Config:     testRequestConfig,
 			}},
 			expectedResources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls:  1,
@@ -171,18 +195,22 @@ requests:
   - type: random
     rotation_window: 240s
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			resources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime.Add(-55 * time.Minute),
+				Config:     testRequestConfig,
 			}},
 			expectedResources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime.Add(-55 * time.Minute),
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls:  0,
@@ -203,18 +231,22 @@ requests:
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			resources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: time.Now(),
+				Config:     testRequestConfig,
 			}},
 			expectedResources: []*sidecred.Resource{{
 				Type:       sidecred.Randomized,
 				ID:         testStateID,
 				Store:      "inprocess",
 				Expiration: testTime,
+				Config:     testRequestConfig,
 				InUse:      true,
 			}},
 			expectedCreateCalls:  1,
@@ -273,6 +305,65 @@ requests:
 			expectedSecrets:   map[string]string{},
 			expectedResources: []*sidecred.Resource{},
 		},
+		{
+			description: "does not create credentials for requests that fail provider validation",
+			config: strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+			`),
+			validateError:       &sidecred.InvalidRequestError{Err: errors.New("invalid request")},
+			expectedSecrets:     map[string]string{},
+			expectedCreateCalls: 0,
+		},
+		{
+			description: "keeps existing credentials and fails if requests cannot be validated",
+			config: strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+			`),
+			resources: []*sidecred.Resource{{
+				Type:       sidecred.Randomized,
+				ID:         testStateID,
+				Store:      "inprocess",
+				Expiration: testTime.Add(-55 * time.Minute),
+				Config:     testRequestConfig,
+			}},
+			validateError: errors.New("get app permissions: 502 Bad Gateway"),
+			expectedResources: []*sidecred.Resource{{
+				Type:       sidecred.Randomized,
+				ID:         testStateID,
+				Store:      "inprocess",
+				Expiration: testTime.Add(-55 * time.Minute),
+				Config:     testRequestConfig,
+				InUse:      true,
+			}},
+			expectedCreateCalls: 0,
+			expectedError:       "failed to validate requests: fake.state.id",
+		},
 		{
 			description: "allows different stores to have overlapping credential names",
 			config: strings.TrimSpace(`
@@ -291,10 +382,14 @@ requests:
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 - store: two
   creds:
   - type: random
     name: fake.state.id
+    config:
+      length: 16
 			`),
 			expectedResources: []*sidecred.Resource{
 				{
@@ -302,6 +397,7 @@ requests:
 					ID:         testStateID,
 					Store:      "one",
 					Expiration: testTime,
+					Config:     testRequestConfig,
 					InUse:      true,
 				},
 				{
@@ -309,6 +405,7 @@ requests:
 					ID:         testStateID,
 					Store:      "two",
 					Expiration: testTime,
+					Config:     testRequestConfig,
 					InUse:      true,
 				},
 			},
@@ -321,7 +418,7 @@ requests:
 			var (
 				store    = inprocess.New()
 				state    = sidecred.NewState()
-				provider = &fakeProvider{}
+				provider = &fakeProvider{validateError: tc.validateError}
 			)
 			for _, r := range tc.resources {
 				state.AddResource(r)
@@ -334,7 +431,11 @@ requests:
 			require.NoError(t, err)
 
 			err = s.Process(eventctx.TestContext(t), cfg, state)
-			require.NoError(t, err)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
 			assert.Equal(t, tc.expectedCreateCalls, provider.CreateCallCount(), "create calls")
 			assert.Equal(t, tc.expectedDestroyCalls, provider.DestroyCallCount(), "destroy calls")
 
@@ -456,8 +557,266 @@ stores:
 	}
 }
 
+func TestProcessReconcile(t *testing.T) {
+	tests := []struct {
+		description            string
+		writeError             error
+		expectedReconcileCalls int
+	}{
+		{
+			description:            "reconciles after writing credentials",
+			expectedReconcileCalls: 1,
+		},
+		{
+			description: "does not reconcile if the credentials could not be written",
+			writeError:  errors.New("failure"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			var (
+				store    = &fakeFailingStore{SecretStore: inprocess.New(), writeError: tc.writeError}
+				state    = sidecred.NewState()
+				provider = &fakeReconcilingProvider{}
+			)
+
+			s, err := sidecred.New([]sidecred.Provider{provider}, []sidecred.SecretStore{store}, 10*time.Minute)
+			require.NoError(t, err)
+
+			cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+			`)))
+			require.NoError(t, err)
+
+			err = s.Process(eventctx.TestContext(t), cfg, state)
+			require.NoError(t, err)
+			assert.Equal(t, 1, provider.CreateCallCount(), "create calls")
+			require.Len(t, provider.reconciled, tc.expectedReconcileCalls, "reconcile calls")
+			if tc.expectedReconcileCalls > 0 {
+				assert.Equal(t, "team-name", provider.namespace)
+				assert.Equal(t, state.Providers[0].Resources, provider.reconciled[0])
+			}
+		})
+	}
+}
+
+func TestProcessAccessTokenRevocation(t *testing.T) {
+	var (
+		ctx   = eventctx.TestContext(t)
+		store = &fakeGithubStore{SecretStore: inprocess.New()}
+		state = sidecred.NewState()
+		app   = &githubfakes.FakeApp{}
+	)
+	// The first token expires within the rotation window, so that it is replaced on the second run.
+	app.CreateInstallationTokenReturnsOnCall(0, &githubapp.Token{InstallationToken: &github.InstallationToken{
+		Token:     github.String("token-1"),
+		ExpiresAt: timePtr(time.Now().Add(5 * time.Minute)),
+	}}, nil)
+	app.CreateInstallationTokenReturnsOnCall(1, &githubapp.Token{InstallationToken: &github.InstallationToken{
+		Token:     github.String("token-2"),
+		ExpiresAt: timePtr(time.Now().Add(time.Hour)),
+	}}, nil)
+
+	var revoked []string
+	provider := githubprovider.New(app,
+		githubprovider.WithAccessTokenRevocation(true),
+		githubprovider.WithAppsClientFactory(func(token string) githubprovider.AppsAPI {
+			revoked = append(revoked, token)
+			return &githubfakes.FakeAppsAPI{}
+		}),
+	)
+
+	s, err := sidecred.New([]sidecred.Provider{provider}, []sidecred.SecretStore{store}, 10*time.Minute)
+	require.NoError(t, err)
+
+	cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: github
+
+requests:
+- store: github
+  creds:
+  - type: github:access-token
+    name: access-token
+    config:
+      owner: telia-oss
+	`)))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Process(ctx, cfg, state))
+	assert.Empty(t, revoked)
+
+	require.NoError(t, s.Process(ctx, cfg, state))
+	assert.Equal(t, 2, app.CreateInstallationTokenCallCount(), "create calls")
+	assert.Equal(t, []string{"token-1"}, revoked)
+
+	require.Len(t, state.Providers, 1)
+	require.Len(t, state.Providers[0].Resources, 1)
+	assert.Equal(t, &sidecred.Metadata{"access_token": "token-2"}, state.Providers[0].Resources[0].Metadata)
+}
+
+func TestProcessDeferredDestroy(t *testing.T) {
+	var (
+		store    = inprocess.New()
+		state    = sidecred.NewState()
+		provider = &fakeDeferringProvider{}
+		current  = &sidecred.Resource{Type: sidecred.Randomized, ID: testStateID, Store: "inprocess", Expiration: testTime, Config: testRequestConfig}
+		previous = &sidecred.Resource{Type: sidecred.Randomized, ID: testStateID, Store: "inprocess", Expiration: time.Now().Add(5 * time.Minute), Deposed: true}
+		expired  = &sidecred.Resource{Type: sidecred.Randomized, ID: testStateID, Store: "inprocess", Expiration: time.Now().Add(-5 * time.Minute), Deposed: true}
+		removed  = &sidecred.Resource{Type: sidecred.Randomized, ID: "removed", Store: "inprocess", Expiration: time.Now().Add(5 * time.Minute), Deposed: true}
+	)
+	for _, r := range []*sidecred.Resource{expired, previous, removed, current} {
+		state.AddResource(r)
+	}
+	current.Deposed = false
+
+	s, err := sidecred.New([]sidecred.Provider{provider}, []sidecred.SecretStore{store}, 10*time.Minute)
+	require.NoError(t, err)
+
+	cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+	`)))
+	require.NoError(t, err)
+
+	err = s.Process(eventctx.TestContext(t), cfg, state)
+	require.NoError(t, err)
+	assert.Equal(t, 0, provider.CreateCallCount(), "create calls")
+	assert.Equal(t, 2, provider.DestroyCallCount(), "destroy calls")
+	require.Len(t, state.Providers, 1)
+	assert.Equal(t, []*sidecred.Resource{previous, current}, state.Providers[0].Resources)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		description      string
+		validateError    error
+		expectedWarnings []string
+		expectedError    string
+	}{
+		{
+			description:      "validates requests",
+			expectedWarnings: []string{`random request "fake.state.id": fake warning`},
+		},
+		{
+			description:   "returns validation errors",
+			validateError: errors.New("invalid"),
+			expectedError: `random request "fake.state.id": invalid`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			provider := &fakeProvider{validateError: tc.validateError, warnings: []string{"fake warning"}}
+
+			s, err := sidecred.New([]sidecred.Provider{provider}, nil, 10*time.Minute)
+			require.NoError(t, err)
+
+			cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+			`)))
+			require.NoError(t, err)
+
+			warnings, err := s.Validate(eventctx.TestContext(t), cfg)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedWarnings, warnings)
+			assert.Equal(t, 0, provider.CreateCallCount(), "create calls")
+		})
+	}
+}
+
+func TestProcessBundle(t *testing.T) {
+	var (
+		store    = &fakeBundlingStore{SecretStore: inprocess.New()}
+		state    = sidecred.NewState()
+		provider = &fakeProvider{}
+	)
+
+	s, err := sidecred.New([]sidecred.Provider{provider}, []sidecred.SecretStore{store}, 10*time.Minute)
+	require.NoError(t, err)
+
+	cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: inprocess
+  config:
+    bundle: true
+
+requests:
+- store: inprocess
+  creds:
+  - type: random
+    name: fake.state.id
+    config:
+      length: 16
+	`)))
+	require.NoError(t, err)
+
+	err = s.Process(eventctx.TestContext(t), cfg, state)
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.writeBundleCallCount, "write bundle calls")
+	require.Len(t, state.Stores, 1)
+	assert.Equal(t, []*sidecred.Secret{{
+		ResourceID: testStateID,
+		Path:       "team-name.fake.state.id",
+		Expiration: testTime,
+	}}, state.Stores[0].Secrets)
+}
+
 // Fake implementation of sidecred.Provider.
 type fakeProvider struct {
+	validateError    error
+	warnings         []string
 	createCallCount  int
 	destroyCallCount int
 }
@@ -466,6 +825,14 @@ func (f *fakeProvider) Type() sidecred.ProviderType {
 	return sidecred.Random
 }
 
+func (f *fakeProvider) ValidateRequest(_ context.Context, _ *sidecred.CredentialRequest) error {
+	return f.validateError
+}
+
+func (f *fakeProvider) LintRequest(_ *sidecred.CredentialRequest) []string {
+	return f.warnings
+}
+
 func (f *fakeProvider) Create(_ context.Context, _ *sidecred.CredentialRequest) ([]*sidecred.Credential, *sidecred.Metadata, error) {
 	f.createCallCount++
 	return []*sidecred.Credential{{
@@ -489,3 +856,73 @@ func (f *fakeProvider) CreateCallCount() int {
 func (f *fakeProvider) DestroyCallCount() int {
 	return f.destroyCallCount
 }
+
+// Fake implementation of sidecred.Reconciler.
+type fakeReconcilingProvider struct {
+	fakeProvider
+	namespace  string
+	reconciled [][]*sidecred.Resource
+}
+
+func (f *fakeReconcilingProvider) Reconcile(ctx context.Context, _ *sidecred.CredentialRequest, resources []*sidecred.Resource) error {
+	f.namespace = eventctx.GetNamespace(ctx)
+	f.reconciled = append(f.reconciled, resources)
+	return nil
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// Fake implementation of sidecred.SecretStore which (like Github secrets) does not return the values of secrets.
+type fakeGithubStore struct {
+	sidecred.SecretStore
+}
+
+func (f *fakeGithubStore) Type() sidecred.StoreType {
+	return sidecred.GithubSecrets
+}
+
+func (f *fakeGithubStore) Read(ctx context.Context, path string, config json.RawMessage) (string, bool, error) {
+	if _, found, err := f.SecretStore.Read(ctx, path, config); err != nil || !found {
+		return "", found, err
+	}
+	return path, true, nil
+}
+
+// Fake implementation of sidecred.SecretStore which fails to write secrets.
+type fakeFailingStore struct {
+	sidecred.SecretStore
+	writeError error
+}
+
+func (f *fakeFailingStore) Write(ctx context.Context, namespace string, secret *sidecred.Credential, config json.RawMessage) (string, error) {
+	if f.writeError != nil {
+		return "", f.writeError
+	}
+	return f.SecretStore.Write(ctx, namespace, secret, config)
+}
+
+// Fake implementation of sidecred.DestroyDeferrer.
+type fakeDeferringProvider struct {
+	fakeProvider
+}
+
+func (f *fakeDeferringProvider) DeferDestroy(_ *sidecred.Resource) bool {
+	return true
+}
+
+// Fake implementation of sidecred.SecretBundler.
+type fakeBundlingStore struct {
+	sidecred.SecretStore
+	writeBundleCallCount int
+}
+
+func (f *fakeBundlingStore) Bundle(config json.RawMessage) bool {
+	return strings.Contains(string(config), `"bundle":true`)
+}
+
+func (f *fakeBundlingStore) WriteBundle(_ context.Context, namespace, name string, _ []*sidecred.Credential, _ json.RawMessage) (string, error) {
+	f.writeBundleCallCount++
+	return namespace + "." + name, nil
+}
@@ -108,12 +108,19 @@ func (s *State) GetResourcesByID(t CredentialType, id, store string) []*Resource
 	return resources
 }
 
-// RemoveResource from the state.
+// RemoveResource from the state. Deposed resources can share the ID of the current
+// resource, so the resource itself is removed if it exists in the state.
 func (s *State) RemoveResource(resource *Resource) {
 	state, ok := s.getProviderState(resource.Type.Provider())
 	if !ok {
 		return
 	}
+	for i, res := range state.Resources {
+		if res == resource {
+			state.Resources = append(state.Resources[:i], state.Resources[i+1:]...)
+			return
+		}
+	}
 	for i, res := range state.Resources {
 		if res.Type == resource.Type && res.Store == resource.Store && res.ID == resource.ID {
 			state.Resources = append(state.Resources[:i], state.Resources[i+1:]...)
@@ -137,6 +144,7 @@ type Secret struct {
 	ResourceID string    `json:"resource_id"`
 	Path       string    `json:"path"`
 	Expiration time.Time `json:"expiration"`
+	Deposed    bool      `json:"deposed,omitempty"`
 }
 
 func (s *State) getSecretStoreState(c *StoreConfig) (*storeState, bool) {
@@ -166,8 +174,28 @@ func (s *State) AddSecret(c *StoreConfig, secret *Secret) {
 	state.Secrets = append(state.Secrets, secret)
 }
 
+// DeposeSecrets marks the secrets for a resource ID as deposed, except for the secrets at the given
+// paths (i.e. the secrets that replaced them). This is only needed for stores that write credentials
+// to a new path when they are rotated (e.g. versioned paths).
+func (s *State) DeposeSecrets(c *StoreConfig, resourceID string, paths []string) {
+	state, ok := s.getSecretStoreState(c)
+	if !ok {
+		return
+	}
+	current := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		current[p] = struct{}{}
+	}
+	for _, sec := range state.Secrets {
+		if _, ok := current[sec.Path]; !ok && sec.ResourceID == resourceID {
+			sec.Deposed = true
+		}
+	}
+}
+
 // ListOrphanedSecrets lists all secrets tied to missing resource
-// IDs that should be considered orphaned.
+// IDs, and deposed secrets that have expired, which should be
+// considered orphaned.
 func (s *State) ListOrphanedSecrets(c *StoreConfig) []*Secret {
 	validResourceIDs := make(map[string]struct{})
 	for _, p := range s.Providers {
@@ -181,7 +209,7 @@ func (s *State) ListOrphanedSecrets(c *StoreConfig) []*Secret {
 	}
 	var orphaned []*Secret
 	for _, sec := range state.Secrets {
-		if _, ok := validResourceIDs[sec.ResourceID]; ok {
+		if _, ok := validResourceIDs[sec.ResourceID]; ok && !(sec.Deposed && sec.Expiration.Before(time.Now())) {
 			continue
 		}
 		orphaned = append(orphaned, sec)
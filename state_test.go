@@ -63,3 +63,29 @@ func TestState(t *testing.T) {
 		})
 	}
 }
+
+func TestStateDeposeSecrets(t *testing.T) {
+	var (
+		state       = sidecred.NewState()
+		storeConfig = &sidecred.StoreConfig{Type: sidecred.Inprocess}
+		expired     = &sidecred.Secret{ResourceID: testStateID, Path: "path?version=1", Expiration: time.Now().Add(-time.Minute)}
+		previous    = &sidecred.Secret{ResourceID: testStateID, Path: "path?version=2", Expiration: time.Now().Add(time.Minute)}
+		current     = &sidecred.Secret{ResourceID: testStateID, Path: "path?version=3", Expiration: time.Now().Add(time.Hour)}
+		other       = &sidecred.Secret{ResourceID: "other", Path: "other", Expiration: time.Now().Add(-time.Minute)}
+	)
+	state.AddResource(&sidecred.Resource{Type: sidecred.Randomized, ID: testStateID})
+	state.AddResource(&sidecred.Resource{Type: sidecred.Randomized, ID: "other"})
+	for _, s := range []*sidecred.Secret{expired, previous, current, other} {
+		state.AddSecret(storeConfig, s)
+	}
+	assert.Empty(t, state.ListOrphanedSecrets(storeConfig))
+
+	state.DeposeSecrets(storeConfig, testStateID, []string{current.Path})
+	assert.True(t, expired.Deposed)
+	assert.True(t, previous.Deposed)
+	assert.False(t, current.Deposed)
+	assert.False(t, other.Deposed)
+
+	// Deposed secrets are only orphaned once they have expired.
+	assert.Equal(t, []*sidecred.Secret{expired}, state.ListOrphanedSecrets(storeConfig))
+}
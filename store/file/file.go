@@ -0,0 +1,336 @@
+// Package file implements sidecred.SecretStore on the local filesystem.
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/telia-oss/sidecred"
+)
+
+// Formats for aggregated files.
+const (
+	FormatDotenv = "dotenv"
+	FormatJSON   = "json"
+)
+
+// illegalKeyCharactersRegex matches characters that are not allowed in the keys of a dotenv file.
+var illegalKeyCharactersRegex = regexp.MustCompile("[^a-zA-Z0-9_]+")
+
+// New creates a new sidecred.SecretStore which writes files to the given directory.
+func New(directory string, options ...option) sidecred.SecretStore {
+	s := &store{
+		directory:      filepath.Clean(directory),
+		secretTemplate: "{{ .Namespace }}/{{ .Name }}",
+	}
+	for _, optionFunc := range options {
+		optionFunc(s)
+	}
+	return s
+}
+
+type option func(*store)
+
+// WithSecretTemplate sets the path template when instantiating a new store.
+func WithSecretTemplate(t string) option {
+	return func(s *store) {
+		s.secretTemplate = t
+	}
+}
+
+type store struct {
+	directory      string
+	secretTemplate string
+}
+
+// config that can be passed to the Configure method of this store.
+//
+// By default, each credential is written to a separate file (named using the secret template). When format is
+// set to "dotenv" or "json", the credentials are instead aggregated in a single file (named using the file template,
+// which defaults to "{{ .Namespace }}.env" or "{{ .Namespace }}.json"), and the secret template is used for the keys
+// (defaults to "{{ .Name }}"). Paths are relative to the directory of the store, and files are written atomically
+// with 0600 permissions.
+type config struct {
+	SecretTemplate string `json:"secret_template"`
+	Format         string `json:"format"`
+	FileTemplate   string `json:"file"`
+}
+
+// Type implements sidecred.SecretStore.
+func (s *store) Type() sidecred.StoreType {
+	return sidecred.File
+}
+
+// Write implements sidecred.SecretStore.
+func (s *store) Write(_ context.Context, namespace string, secret *sidecred.Credential, config json.RawMessage) (string, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("parse config: %s", err)
+	}
+	name, err := sidecred.BuildSecretTemplate(c.SecretTemplate, namespace, secret.Name)
+	if err != nil {
+		return "", fmt.Errorf("build secret template: %s", err)
+	}
+	if c.Format == "" {
+		path, err := s.resolve(name)
+		if err != nil {
+			return "", err
+		}
+		if err := writeFileAtomic(path, []byte(secret.Value)); err != nil {
+			return "", fmt.Errorf("write file: %s", err)
+		}
+		return path, nil
+	}
+
+	file, err := sidecred.BuildSecretTemplate(c.FileTemplate, namespace, "")
+	if err != nil {
+		return "", fmt.Errorf("build file template: %s", err)
+	}
+	path, err := s.resolve(file)
+	if err != nil {
+		return "", err
+	}
+	key := name
+	if c.Format == FormatDotenv {
+		key = illegalKeyCharactersRegex.ReplaceAllString(key, "_")
+	}
+	values, err := readValues(path, c.Format)
+	if err != nil {
+		return "", fmt.Errorf("read file: %s", err)
+	}
+	values[key] = secret.Value
+	if err := writeValues(path, c.Format, values); err != nil {
+		return "", fmt.Errorf("write file: %s", err)
+	}
+	return path + "#" + key, nil
+}
+
+// Read implements sidecred.SecretStore.
+func (s *store) Read(_ context.Context, path string, config json.RawMessage) (string, bool, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", false, fmt.Errorf("parse config: %s", err)
+	}
+	file, key, err := s.parsePath(path, c)
+	if err != nil {
+		return "", false, err
+	}
+	if key == "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		return string(b), true, nil
+	}
+	values, err := readValues(file, c.Format)
+	if err != nil {
+		return "", false, fmt.Errorf("read file: %s", err)
+	}
+	value, found := values[key]
+	return value, found, nil
+}
+
+// Delete implements sidecred.SecretStore.
+func (s *store) Delete(_ context.Context, path string, config json.RawMessage) error {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse config: %s", err)
+	}
+	file, key, err := s.parsePath(path, c)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	values, err := readValues(file, c.Format)
+	if err != nil {
+		return fmt.Errorf("read file: %s", err)
+	}
+	if _, found := values[key]; !found {
+		return nil
+	}
+	delete(values, key)
+
+	// Remove the file when the last key is deleted.
+	if len(values) == 0 {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := writeValues(file, c.Format, values); err != nil {
+		return fmt.Errorf("write file: %s", err)
+	}
+	return nil
+}
+
+// parseConfig parses and validates the config.
+func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
+	c := &config{}
+	if err := sidecred.UnmarshalConfig(raw, &c); err != nil {
+		return nil, err
+	}
+	switch c.Format {
+	case "":
+		if c.FileTemplate != "" {
+			return nil, fmt.Errorf("%q requires a %q", "file", "format")
+		}
+		if c.SecretTemplate == "" {
+			c.SecretTemplate = s.secretTemplate
+		}
+	case FormatDotenv, FormatJSON:
+		if c.FileTemplate == "" {
+			c.FileTemplate = "{{ .Namespace }}.env"
+			if c.Format == FormatJSON {
+				c.FileTemplate = "{{ .Namespace }}.json"
+			}
+		}
+		if c.SecretTemplate == "" {
+			c.SecretTemplate = "{{ .Name }}"
+		}
+	default:
+		return nil, fmt.Errorf("invalid format: %q", c.Format)
+	}
+	return c, nil
+}
+
+// resolve returns the absolute path for a file, which must be in the directory of the store.
+func (s *store) resolve(name string) (string, error) {
+	path := filepath.Join(s.directory, filepath.FromSlash(name))
+	if !s.contains(path) {
+		return "", fmt.Errorf("path is outside of the directory: %q", name)
+	}
+	return path, nil
+}
+
+func (s *store) contains(path string) bool {
+	rel, err := filepath.Rel(s.directory, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// parsePath parses paths returned by Write, i.e. the path to a file, or the path to an aggregated file and the key.
+func (s *store) parsePath(path string, c *config) (file, key string, err error) {
+	file = path
+	if c.Format != "" {
+		i := strings.LastIndex(path, "#")
+		if i < 0 || i == len(path)-1 {
+			return "", "", fmt.Errorf("invalid path: %q", path)
+		}
+		file, key = path[:i], path[i+1:]
+	}
+	if !s.contains(filepath.Clean(file)) {
+		return "", "", fmt.Errorf("invalid path: %q", path)
+	}
+	return file, key, nil
+}
+
+// readValues reads the values from an aggregated file. Returns no values if the file does not exist.
+func readValues(path, format string) (map[string]string, error) {
+	values := make(map[string]string)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, nil
+		}
+		return nil, err
+	}
+	if format == FormatJSON {
+		if len(bytes.TrimSpace(b)) == 0 {
+			return values, nil
+		}
+		if err := json.Unmarshal(b, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid line: %q", line)
+		}
+		value, err := strconv.Unquote(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %q: %s", parts[0], err)
+		}
+		values[parts[0]] = value
+	}
+	return values, scanner.Err()
+}
+
+// writeValues writes the values to an aggregated file. Values in dotenv files are double quoted, with
+// special characters (e.g. newlines) escaped.
+func writeValues(path, format string, values map[string]string) error {
+	if format == FormatJSON {
+		b, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, append(b, '\n'))
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, strconv.Quote(values[k]))
+	}
+	return writeFileAtomic(path, b.Bytes())
+}
+
+// writeFileAtomic writes the data to a temporary file (with 0600 permissions) in the same
+// directory, and then renames it, so that readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,130 @@
+package file_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/store/file"
+)
+
+func TestFileStore(t *testing.T) {
+	tests := []struct {
+		description     string
+		config          json.RawMessage
+		aggregated      bool
+		expectedPath    string
+		expectedFile    string
+		expectedContent string
+		expectedError   string
+	}{
+		{
+			description:     "file store works",
+			expectedPath:    "team-name/secret-name",
+			expectedFile:    "team-name/secret-name",
+			expectedContent: "secret\nvalue",
+		},
+		{
+			description:     "supports dotenv files",
+			config:          []byte(`{"format":"dotenv","secret_template":"{{ .Namespace }}-{{ .Name }}"}`),
+			aggregated:      true,
+			expectedPath:    "team-name.env#team_name_secret_name",
+			expectedFile:    "team-name.env",
+			expectedContent: "team_name_other=\"other-value\"\nteam_name_secret_name=\"secret\\nvalue\"\n",
+		},
+		{
+			description:     "supports json files",
+			config:          []byte(`{"format":"json","file":"{{ .Namespace }}/credentials.json"}`),
+			aggregated:      true,
+			expectedPath:    "team-name/credentials.json#secret-name",
+			expectedFile:    "team-name/credentials.json",
+			expectedContent: "{\n  \"other\": \"other-value\",\n  \"secret-name\": \"secret\\nvalue\"\n}\n",
+		},
+		{
+			description:   "does not write outside of the directory",
+			config:        []byte(`{"secret_template":"../{{ .Name }}"}`),
+			expectedError: `path is outside of the directory: "../secret-name"`,
+		},
+		{
+			description:   "fails for invalid formats",
+			config:        []byte(`{"format":"yaml"}`),
+			expectedError: `parse config: invalid format: "yaml"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := t.TempDir()
+			s := file.New(dir)
+
+			// Write another credential first, to ensure that aggregated files are merged.
+			var otherPath string
+			if tc.aggregated {
+				path, err := s.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "other", Value: "other-value"}, tc.config)
+				require.NoError(t, err)
+				otherPath = path
+			}
+
+			path, err := s.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: "secret\nvalue"}, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tc.expectedPath), path)
+
+			info, err := os.Stat(filepath.Join(dir, tc.expectedFile))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+			b, err := os.ReadFile(filepath.Join(dir, tc.expectedFile))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedContent, string(b))
+
+			value, found, err := s.Read(context.TODO(), path, tc.config)
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "secret\nvalue", value)
+
+			require.NoError(t, s.Delete(context.TODO(), path, tc.config))
+			_, found, err = s.Read(context.TODO(), path, tc.config)
+			require.NoError(t, err)
+			assert.False(t, found)
+
+			if otherPath != "" {
+				value, found, err := s.Read(context.TODO(), otherPath, tc.config)
+				require.NoError(t, err)
+				assert.True(t, found, "other keys are kept")
+				assert.Equal(t, "other-value", value)
+
+				require.NoError(t, s.Delete(context.TODO(), otherPath, tc.config))
+			}
+			_, err = os.Stat(filepath.Join(dir, tc.expectedFile))
+			assert.True(t, os.IsNotExist(err), "file is removed")
+
+			// Deleting secrets that do not exist is not an error.
+			require.NoError(t, s.Delete(context.TODO(), path, tc.config))
+
+			entries, err := os.ReadDir(filepath.Dir(filepath.Join(dir, tc.expectedFile)))
+			require.NoError(t, err)
+			assert.Empty(t, entries, "no temporary files are left")
+		})
+	}
+}
+
+func TestDeleteOutsideDirectory(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "outside")
+	require.NoError(t, os.WriteFile(outside, []byte("value"), 0o600))
+
+	err := file.New(t.TempDir()).Delete(context.TODO(), outside, nil)
+	assert.EqualError(t, err, "invalid path: \""+outside+"\"")
+
+	_, err = os.Stat(outside)
+	assert.NoError(t, err)
+}
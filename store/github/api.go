@@ -19,3 +19,25 @@ type ActionsAPI interface {
 	GetRepoSecret(ctx context.Context, owner, repo, name string) (*github.Secret, *github.Response, error)
 	DeleteRepoSecret(ctx context.Context, owner, repo, name string) (*github.Response, error)
 }
+
+// EnvironmentsAPI wraps the Github API for environment secrets.
+//
+//counterfeiter:generate . EnvironmentsAPI
+type EnvironmentsAPI interface {
+	GetRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	GetEnvPublicKey(ctx context.Context, repoID int, env string) (*github.PublicKey, *github.Response, error)
+	CreateOrUpdateEnvSecret(ctx context.Context, repoID int, env string, eSecret *github.EncryptedSecret) (*github.Response, error)
+	GetEnvSecret(ctx context.Context, repoID int, env, secretName string) (*github.Secret, *github.Response, error)
+	DeleteEnvSecret(ctx context.Context, repoID int, env, secretName string) (*github.Response, error)
+}
+
+// OrganizationsAPI wraps the Github API for organization secrets.
+//
+//counterfeiter:generate . OrganizationsAPI
+type OrganizationsAPI interface {
+	GetRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
+	GetOrgPublicKey(ctx context.Context, org string) (*github.PublicKey, *github.Response, error)
+	CreateOrUpdateOrgSecret(ctx context.Context, org string, eSecret *github.EncryptedSecret) (*github.Response, error)
+	GetOrgSecret(ctx context.Context, org, name string) (*github.Secret, *github.Response, error)
+	DeleteOrgSecret(ctx context.Context, org, name string) (*github.Response, error)
+}
@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/telia-oss/githubapp"
+
+	"github.com/telia-oss/sidecred"
+)
+
+// NewCodespacesStore creates a new sidecred.SecretStore using Github repository Codespaces secrets.
+func NewCodespacesStore(app App, options ...Option) sidecred.SecretStore {
+	options = append(options, forStoreType(sidecred.GithubCodespacesSecrets), WithActionsClientFactory(func(token string) ActionsAPI {
+		return NewCodespacesClient(githubapp.NewInstallationClient(token).V3)
+	}))
+
+	return NewStore(app, options...)
+}
+
+// NewCodespacesClient returns an ActionsAPI for repository Codespaces secrets. The Codespaces API is not
+// supported by go-github, but the endpoints for repository secrets mirror the ones for Actions.
+func NewCodespacesClient(client *github.Client) ActionsAPI {
+	return &codespacesClient{client: client}
+}
+
+// codespacesClient implements ActionsAPI using the Github Codespaces API.
+type codespacesClient struct {
+	client *github.Client
+}
+
+// GetRepoPublicKey gets the public key used to encrypt Codespaces secrets in a repository.
+//
+// Github API docs: https://docs.github.com/en/rest/codespaces/repository-secrets#get-a-repository-public-key
+func (c *codespacesClient) GetRepoPublicKey(ctx context.Context, owner, repo string) (*github.PublicKey, *github.Response, error) {
+	key := new(github.PublicKey)
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("repos/%v/%v/codespaces/secrets/public-key", owner, repo), nil, key)
+	if err != nil {
+		return nil, resp, err
+	}
+	return key, resp, nil
+}
+
+// CreateOrUpdateRepoSecret creates or updates a Codespaces secret in a repository.
+//
+// Github API docs: https://docs.github.com/en/rest/codespaces/repository-secrets#create-or-update-a-repository-secret
+func (c *codespacesClient) CreateOrUpdateRepoSecret(ctx context.Context, owner, repo string, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("repos/%v/%v/codespaces/secrets/%v", owner, repo, eSecret.Name), eSecret, nil)
+}
+
+// GetRepoSecret gets a single Codespaces secret (without the value) in a repository.
+//
+// Github API docs: https://docs.github.com/en/rest/codespaces/repository-secrets#get-a-repository-secret
+func (c *codespacesClient) GetRepoSecret(ctx context.Context, owner, repo, name string) (*github.Secret, *github.Response, error) {
+	secret := new(github.Secret)
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("repos/%v/%v/codespaces/secrets/%v", owner, repo, name), nil, secret)
+	if err != nil {
+		return nil, resp, err
+	}
+	return secret, resp, nil
+}
+
+// DeleteRepoSecret deletes a Codespaces secret in a repository.
+//
+// Github API docs: https://docs.github.com/en/rest/codespaces/repository-secrets#delete-a-repository-secret
+func (c *codespacesClient) DeleteRepoSecret(ctx context.Context, owner, repo, name string) (*github.Response, error) {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("repos/%v/%v/codespaces/secrets/%v", owner, repo, name), nil, nil)
+}
+
+func (c *codespacesClient) do(ctx context.Context, method, url string, body, v interface{}) (*github.Response, error) {
+	req, err := c.client.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.Do(ctx, req, v)
+}
@@ -0,0 +1,74 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/telia-oss/githubapp"
+
+	"github.com/telia-oss/sidecred"
+)
+
+// NewEnvironmentStore creates a new sidecred.SecretStore using Github environment secrets.
+func NewEnvironmentStore(app App, options ...Option) sidecred.SecretStore {
+	options = append(options, forStoreType(sidecred.GithubEnvironmentSecrets))
+
+	return NewStore(app, options...)
+}
+
+// environmentsClient implements EnvironmentsAPI using the Github API.
+type environmentsClient struct {
+	*github.Client
+}
+
+func (c *environmentsClient) GetRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	return c.Repositories.Get(ctx, owner, repo)
+}
+
+func (c *environmentsClient) GetEnvPublicKey(ctx context.Context, repoID int, env string) (*github.PublicKey, *github.Response, error) {
+	return c.Actions.GetEnvPublicKey(ctx, repoID, env)
+}
+
+func (c *environmentsClient) CreateOrUpdateEnvSecret(ctx context.Context, repoID int, env string, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	return c.Actions.CreateOrUpdateEnvSecret(ctx, repoID, env, eSecret)
+}
+
+func (c *environmentsClient) GetEnvSecret(ctx context.Context, repoID int, env, secretName string) (*github.Secret, *github.Response, error) {
+	return c.Actions.GetEnvSecret(ctx, repoID, env, secretName)
+}
+
+func (c *environmentsClient) DeleteEnvSecret(ctx context.Context, repoID int, env, secretName string) (*github.Response, error) {
+	return c.Actions.DeleteEnvSecret(ctx, repoID, env, secretName)
+}
+
+func newEnvironmentsClient(token string) EnvironmentsAPI {
+	return &environmentsClient{Client: githubapp.NewInstallationClient(token).V3}
+}
+
+// environmentSecretsClient implements secretsClient for environment secrets.
+type environmentSecretsClient struct {
+	api          EnvironmentsAPI
+	repositoryID int
+	slug         string
+	environment  string
+}
+
+func (c *environmentSecretsClient) ID() string {
+	return c.slug + ":" + c.environment
+}
+
+func (c *environmentSecretsClient) GetPublicKey(ctx context.Context) (*github.PublicKey, *github.Response, error) {
+	return c.api.GetEnvPublicKey(ctx, c.repositoryID, c.environment)
+}
+
+func (c *environmentSecretsClient) CreateOrUpdateSecret(ctx context.Context, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	return c.api.CreateOrUpdateEnvSecret(ctx, c.repositoryID, c.environment, eSecret)
+}
+
+func (c *environmentSecretsClient) GetSecret(ctx context.Context, name string) (*github.Secret, *github.Response, error) {
+	return c.api.GetEnvSecret(ctx, c.repositoryID, c.environment, name)
+}
+
+func (c *environmentSecretsClient) DeleteSecret(ctx context.Context, name string) (*github.Response, error) {
+	return c.api.DeleteEnvSecret(ctx, c.repositoryID, c.environment, name)
+}
@@ -31,6 +31,9 @@ func NewStore(app App, options ...Option) sidecred.SecretStore {
 		actionsClientFactory: func(token string) ActionsAPI {
 			return githubapp.NewInstallationClient(token).V3.Actions
 		},
+		environmentsClientFactory:  newEnvironmentsClient,
+		organizationsClientFactory: newOrganizationsClient,
+		repositoryIDs:              make(map[string]int64),
 	}
 	for _, optionFunc := range options {
 		optionFunc(s)
@@ -59,6 +62,20 @@ func WithActionsClientFactory(f func(token string) ActionsAPI) Option {
 	}
 }
 
+// WithEnvironmentsClientFactory sets the function used to create new installation clients for environment secrets, and can be used to return test fakes.
+func WithEnvironmentsClientFactory(f func(token string) EnvironmentsAPI) Option {
+	return func(s *store) {
+		s.environmentsClientFactory = f
+	}
+}
+
+// WithOrganizationsClientFactory sets the function used to create new installation clients for organization secrets, and can be used to return test fakes.
+func WithOrganizationsClientFactory(f func(token string) OrganizationsAPI) Option {
+	return func(s *store) {
+		s.organizationsClientFactory = f
+	}
+}
+
 // forStoreType sets the storeType of this GitHub store
 func forStoreType(storeType sidecred.StoreType) Option {
 	return func(s *store) {
@@ -67,23 +84,39 @@ func forStoreType(storeType sidecred.StoreType) Option {
 }
 
 type store struct {
-	app                  App
-	storeType            sidecred.StoreType
-	keys                 map[string]*github.PublicKey
-	actionsClientFactory func(token string) ActionsAPI
-	secretTemplate       string
+	app                        App
+	storeType                  sidecred.StoreType
+	keys                       map[string]*github.PublicKey
+	repositoryIDs              map[string]int64
+	actionsClientFactory       func(token string) ActionsAPI
+	environmentsClientFactory  func(token string) EnvironmentsAPI
+	organizationsClientFactory func(token string) OrganizationsAPI
+	secretTemplate             string
 }
 
-// config that can be passed to the Configure method of this store.
+// config that can be passed to the Configure method of this store. Organization secrets are
+// configured using organization (instead of repository), visibility and selected_repositories.
 type config struct {
-	SecretTemplate string `json:"secret_template"`
-	RepositorySlug string `json:"repository"`
+	SecretTemplate       string   `json:"secret_template"`
+	RepositorySlug       string   `json:"repository"`
+	Environment          string   `json:"environment"`
+	Organization         string   `json:"organization"`
+	Visibility           string   `json:"visibility"`
+	SelectedRepositories []string `json:"selected_repositories"`
 
 	// Fields populated when the config is parsed
 	owner      string
 	repository string
 }
 
+// repositories returns the repositories that the installation token should be scoped to.
+func (c *config) repositories() []string {
+	if c.repository == "" {
+		return nil
+	}
+	return []string{c.repository}
+}
+
 // Type implements sidecred.SecretStore.
 func (s *store) Type() sidecred.StoreType {
 	return s.storeType
@@ -107,20 +140,25 @@ func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Cr
 	//
 	// It is not supported as of v32 of go-github:
 	// https://github.com/google/go-github/blob/v32.1.0/github/apps.go#L60
-	token, err := s.app.CreateInstallationToken(ctx, c.owner, []string{c.repository}, nil)
+	token, err := s.app.CreateInstallationToken(ctx, c.owner, c.repositories(), nil)
 	if err != nil {
 		return "", fmt.Errorf("create secrets access token: %w", err)
 	}
 	log.Debug("created installation token")
 
-	if _, found := s.keys[c.RepositorySlug]; !found {
-		key, _, err := s.getRepoPublicKey(ctx, token.GetToken(), c.owner, c.repository)
+	client, err := s.newSecretsClient(ctx, token.GetToken(), c)
+	if err != nil {
+		return "", err
+	}
+	if _, found := s.keys[client.ID()]; !found {
+		eventctx.GetStats(ctx).IncGithubCalls()
+		key, _, err := client.GetPublicKey(ctx)
 		if err != nil {
 			return "", fmt.Errorf("get public key: %w", err)
 		}
-		s.keys[c.RepositorySlug] = key
+		s.keys[client.ID()] = key
 	}
-	publicKey := s.keys[c.RepositorySlug]
+	publicKey := s.keys[client.ID()]
 	log.Debug("set public key")
 
 	encryptedSecret, err := s.encryptSecretValue(secret, publicKey)
@@ -133,51 +171,124 @@ func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Cr
 		return "", fmt.Errorf("sanitize path: %w", err)
 	}
 
-	_, err = s.createOrUpdateRepoSecret(ctx, token.GetToken(), c.owner, c.repository, &github.EncryptedSecret{
+	eventctx.GetStats(ctx).IncGithubCalls()
+	_, err = client.CreateOrUpdateSecret(ctx, &github.EncryptedSecret{
 		Name:           path,
 		KeyID:          publicKey.GetKeyID(),
 		EncryptedValue: encryptedSecret,
 	})
-	log.Debug("created or updated repo secret")
+	log.Debug("created or updated secret")
 	if err != nil {
-		return "", fmt.Errorf("Actions.CreateOrUpdateRepoSecret returned error: %w", err)
+		return "", fmt.Errorf("create or update secret: %w", err)
 	}
 
 	return path, nil
 }
 
-func (s *store) getRepoPublicKey(ctx context.Context, token, owner, repo string) (*github.PublicKey, *github.Response, error) {
-	eventctx.GetStats(ctx).IncGithubCalls()
-	return s.actionsClientFactory(token).GetRepoPublicKey(ctx, owner, repo)
+// newSecretsClient returns a secretsClient for the type of secrets managed by the store.
+func (s *store) newSecretsClient(ctx context.Context, token string, c *config) (secretsClient, error) {
+	switch s.storeType {
+	case sidecred.GithubEnvironmentSecrets:
+		api := s.environmentsClientFactory(token)
+		id, err := s.getRepositoryID(ctx, api, c.owner, c.repository)
+		if err != nil {
+			return nil, err
+		}
+		return &environmentSecretsClient{
+			api:          api,
+			repositoryID: int(id),
+			slug:         c.RepositorySlug,
+			environment:  c.Environment,
+		}, nil
+	case sidecred.GithubOrganizationSecrets:
+		api := s.organizationsClientFactory(token)
+		var ids []int64
+		for _, repository := range c.SelectedRepositories {
+			id, err := s.getRepositoryID(ctx, api, c.owner, repository)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return &organizationSecretsClient{
+			api:                   api,
+			organization:          c.owner,
+			visibility:            c.Visibility,
+			selectedRepositoryIDs: ids,
+		}, nil
+	default:
+		return &repositorySecretsClient{
+			api:        s.actionsClientFactory(token),
+			owner:      c.owner,
+			repository: c.repository,
+		}, nil
+	}
 }
 
-func (s *store) createOrUpdateRepoSecret(ctx context.Context, token, owner, repo string, secret *github.EncryptedSecret) (*github.Response, error) {
-	eventctx.GetStats(ctx).IncGithubCalls()
-	return s.actionsClientFactory(token).CreateOrUpdateRepoSecret(ctx, owner, repo, secret)
+// getRepositoryID returns the (cached) ID of a repository.
+func (s *store) getRepositoryID(ctx context.Context, api repositoryGetter, owner, repository string) (int64, error) {
+	slug := owner + "/" + repository
+	if _, found := s.repositoryIDs[slug]; !found {
+		eventctx.GetStats(ctx).IncGithubCalls()
+		r, _, err := api.GetRepository(ctx, owner, repository)
+		if err != nil {
+			return 0, fmt.Errorf("get repository %q: %w", slug, err)
+		}
+		s.repositoryIDs[slug] = r.GetID()
+	}
+	return s.repositoryIDs[slug], nil
+}
+
+// repositoryGetter is implemented by the APIs that need to look up repository IDs.
+type repositoryGetter interface {
+	GetRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 }
 
 // Read implements sidecred.SecretStore.
 //
+// Github does not return the values of secrets, so the name of the secret is returned as the value.
+// Use ReadMetadata to check whether a secret exists and when it was last updated.
+//
 // TODO: Remove Read from SecretStore interface and return structs from New etc. Then rewrite Read for tests only.
 func (s *store) Read(ctx context.Context, path string, config json.RawMessage) (string, bool, error) {
+	secret, err := s.getSecret(ctx, path, config)
+	if err != nil || secret == nil {
+		return "", false, err
+	}
+	return secret.Name, true, nil
+}
+
+// ReadMetadata implements sidecred.SecretMetadataReader.
+func (s *store) ReadMetadata(ctx context.Context, path string, config json.RawMessage) (*sidecred.SecretMetadata, bool, error) {
+	secret, err := s.getSecret(ctx, path, config)
+	if err != nil || secret == nil {
+		return nil, false, err
+	}
+	return &sidecred.SecretMetadata{UpdatedAt: secret.UpdatedAt.Time}, true, nil
+}
+
+// getSecret returns the secret (without the value), or nil if the secret does not exist.
+func (s *store) getSecret(ctx context.Context, path string, config json.RawMessage) (*github.Secret, error) {
 	c, err := s.parseConfig(config)
 	if err != nil {
-		return "", false, fmt.Errorf("parse config: %w", err)
+		return nil, fmt.Errorf("parse config: %w", err)
 	}
-	token, err := s.app.CreateInstallationToken(ctx, c.owner, []string{c.repository}, nil)
+	token, err := s.app.CreateInstallationToken(ctx, c.owner, c.repositories(), nil)
 	if err != nil {
-		return "", false, fmt.Errorf("create secrets access token: %w", err)
+		return nil, fmt.Errorf("create secrets access token: %w", err)
 	}
-	secret, _, err := s.actionsClientFactory(token.GetToken()).GetRepoSecret(
-		ctx,
-		c.owner,
-		c.repository,
-		path,
-	)
+	client, err := s.newSecretsClient(ctx, token.GetToken(), c)
 	if err != nil {
-		return "", false, fmt.Errorf("get secret: %w", err)
+		return nil, err
 	}
-	return secret.Name, true, nil
+	secret, resp, err := client.GetSecret(ctx, path)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get secret: %w", err)
+	}
+	return secret, nil
 }
 
 // Delete implements sidecred.SecretStore.
@@ -186,11 +297,15 @@ func (s *store) Delete(ctx context.Context, path string, config json.RawMessage)
 	if err != nil {
 		return fmt.Errorf("parse config: %w", err)
 	}
-	token, err := s.app.CreateInstallationToken(ctx, c.owner, []string{c.repository}, nil)
+	token, err := s.app.CreateInstallationToken(ctx, c.owner, c.repositories(), nil)
 	if err != nil {
 		return fmt.Errorf("create secrets access token: %w", err)
 	}
-	resp, err := s.actionsClientFactory(token.GetToken()).DeleteRepoSecret(ctx, c.owner, c.repository, path)
+	client, err := s.newSecretsClient(ctx, token.GetToken(), c)
+	if err != nil {
+		return err
+	}
+	resp, err := client.DeleteSecret(ctx, path)
 	if err != nil {
 		// Assume that the secret no longer exists if a 404 error is encountered
 		if resp == nil || resp.StatusCode != 404 {
@@ -206,20 +321,56 @@ func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
 	if err := sidecred.UnmarshalConfig(raw, &c); err != nil {
 		return nil, err
 	}
-	if c.RepositorySlug == "" {
-		return nil, fmt.Errorf("%q must be defined", "repository")
+	if s.storeType == sidecred.GithubOrganizationSecrets {
+		if err := c.parseOrganization(); err != nil {
+			return nil, err
+		}
+	} else {
+		if c.RepositorySlug == "" {
+			return nil, fmt.Errorf("%q must be defined", "repository")
+		}
+		parts := strings.Split(c.RepositorySlug, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid repository slug: %q", c.RepositorySlug)
+		}
+		c.owner, c.repository = parts[0], parts[1]
 	}
-	parts := strings.Split(c.RepositorySlug, "/")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid repository slug: %q", c.RepositorySlug)
+	if s.storeType == sidecred.GithubEnvironmentSecrets && c.Environment == "" {
+		return nil, fmt.Errorf("%q must be defined", "environment")
 	}
-	c.owner, c.repository = parts[0], parts[1]
 	if c.SecretTemplate == "" {
 		c.SecretTemplate = s.secretTemplate
 	}
 	return c, nil
 }
 
+// parseOrganization parses and validates the config for organization secrets.
+func (c *config) parseOrganization() error {
+	if c.Organization == "" {
+		return fmt.Errorf("%q must be defined", "organization")
+	}
+	c.owner = c.Organization
+	switch c.Visibility {
+	case "":
+		c.Visibility = VisibilityPrivate
+	case VisibilityAll, VisibilityPrivate, VisibilitySelected:
+	default:
+		return fmt.Errorf("invalid visibility: %q", c.Visibility)
+	}
+	if c.Visibility == VisibilitySelected && len(c.SelectedRepositories) == 0 {
+		return fmt.Errorf("%q must be defined when visibility is %q", "selected_repositories", VisibilitySelected)
+	}
+	if c.Visibility != VisibilitySelected && len(c.SelectedRepositories) > 0 {
+		return fmt.Errorf("%q requires visibility %q", "selected_repositories", VisibilitySelected)
+	}
+	for _, r := range c.SelectedRepositories {
+		if r == "" || strings.Contains(r, "/") {
+			return fmt.Errorf("invalid repository name: %q", r)
+		}
+	}
+	return nil
+}
+
 // encryptSecretValue encrypts the secret with a public key from Github.
 func (s *store) encryptSecretValue(secret *sidecred.Credential, publicKey *github.PublicKey) (string, error) {
 	keyBytes, err := base64.StdEncoding.DecodeString(publicKey.GetKey())
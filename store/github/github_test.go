@@ -2,11 +2,18 @@ package github_test
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v45/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/telia-oss/githubapp"
 
 	"github.com/telia-oss/sidecred"
@@ -118,6 +125,75 @@ func TestRead(t *testing.T) {
 	}
 }
 
+func TestReadMetadata(t *testing.T) {
+	var (
+		secretPath = "CONCOURSE_TEAM_NAME_SECRET_NAME"
+		updatedAt  = time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+		notFound   = &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	)
+
+	tests := []struct {
+		description      string
+		config           json.RawMessage
+		getSecretResp    *github.Response
+		getSecretError   error
+		expectedMetadata *sidecred.SecretMetadata
+		expectFound      bool
+		expectedError    string
+	}{
+		{
+			description:      "works as expected",
+			config:           []byte(`{"repository":"owner/repository"}`),
+			expectedMetadata: &sidecred.SecretMetadata{UpdatedAt: updatedAt},
+			expectFound:      true,
+		},
+		{
+			description:    "returns not found for missing secrets",
+			config:         []byte(`{"repository":"owner/repository"}`),
+			getSecretResp:  notFound,
+			getSecretError: errors.New("not found"),
+		},
+		{
+			description:    "propagates other errors",
+			config:         []byte(`{"repository":"owner/repository"}`),
+			getSecretError: errors.New("failure"),
+			expectedError:  "get secret: failure",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: installationToken}, nil)
+
+			fakeActionsAPI := &githubfakes.FakeActionsAPI{}
+			fakeActionsAPI.GetRepoSecretReturns(&github.Secret{Name: secretPath, UpdatedAt: github.Timestamp{Time: updatedAt}}, tc.getSecretResp, tc.getSecretError)
+
+			store := secretstore.NewStore(fakeApp,
+				secretstore.WithActionsClientFactory(func(string) secretstore.ActionsAPI {
+					return fakeActionsAPI
+				}),
+			)
+			reader, ok := store.(sidecred.SecretMetadataReader)
+			require.True(t, ok, "implements sidecred.SecretMetadataReader")
+
+			metadata, found, err := reader.ReadMetadata(context.TODO(), secretPath, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectFound, found)
+			assert.Equal(t, tc.expectedMetadata, metadata)
+
+			if found {
+				_, owner, repository, name := fakeActionsAPI.GetRepoSecretArgsForCall(0)
+				assert.Equal(t, []string{"owner", "repository", secretPath}, []string{owner, repository, name})
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	secretPath := "CONCOURSE_TEAM_NAME_SECRET_NAME"
 
@@ -155,3 +231,226 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentStore(t *testing.T) {
+	tests := []struct {
+		description   string
+		config        json.RawMessage
+		expectedPath  string
+		expectedError string
+	}{
+		{
+			description:  "github environment secrets works",
+			config:       []byte(`{"repository":"owner/repository","environment":"production"}`),
+			expectedPath: "TEAM_NAME_SECRET_NAME",
+		},
+		{
+			description:   "requires an environment",
+			config:        []byte(`{"repository":"owner/repository"}`),
+			expectedError: `parse config: "environment" must be defined`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: installationToken}, nil)
+
+			fakeEnvironmentsAPI := &githubfakes.FakeEnvironmentsAPI{}
+			fakeEnvironmentsAPI.GetRepositoryReturns(&github.Repository{ID: github.Int64(42)}, nil, nil)
+			fakeEnvironmentsAPI.GetEnvPublicKeyReturns(&github.PublicKey{KeyID: github.String("key-id"), Key: github.String("")}, nil, nil)
+
+			store := secretstore.NewEnvironmentStore(fakeApp,
+				secretstore.WithEnvironmentsClientFactory(func(string) secretstore.EnvironmentsAPI {
+					return fakeEnvironmentsAPI
+				}),
+			)
+			assert.Equal(t, sidecred.GithubEnvironmentSecrets, store.Type())
+
+			path, err := store.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: "secret-value"}, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+
+			require.Equal(t, 1, fakeEnvironmentsAPI.CreateOrUpdateEnvSecretCallCount())
+			_, repoID, env, secret := fakeEnvironmentsAPI.CreateOrUpdateEnvSecretArgsForCall(0)
+			assert.Equal(t, 42, repoID)
+			assert.Equal(t, "production", env)
+			assert.Equal(t, tc.expectedPath, secret.Name)
+			assert.Equal(t, "key-id", secret.KeyID)
+
+			err = store.Delete(context.TODO(), path, tc.config)
+			assert.NoError(t, err)
+			require.Equal(t, 1, fakeEnvironmentsAPI.DeleteEnvSecretCallCount())
+			_, repoID, env, name := fakeEnvironmentsAPI.DeleteEnvSecretArgsForCall(0)
+			assert.Equal(t, 42, repoID)
+			assert.Equal(t, "production", env)
+			assert.Equal(t, tc.expectedPath, name)
+
+			// Repository IDs and public keys are cached by the store.
+			assert.Equal(t, 1, fakeEnvironmentsAPI.GetRepositoryCallCount())
+			assert.Equal(t, 1, fakeEnvironmentsAPI.GetEnvPublicKeyCallCount())
+		})
+	}
+}
+
+func TestOrganizationStore(t *testing.T) {
+	tests := []struct {
+		description                string
+		config                     json.RawMessage
+		expectedVisibility         string
+		expectedSelectedRepoIDs    github.SelectedRepoIDs
+		expectedGetRepositoryCalls int
+		expectedInstallationRepos  []string
+		expectedError              string
+	}{
+		{
+			description:        "github organization secrets works",
+			config:             []byte(`{"organization":"owner"}`),
+			expectedVisibility: "private",
+		},
+		{
+			description:        "supports visibility",
+			config:             []byte(`{"organization":"owner","visibility":"all"}`),
+			expectedVisibility: "all",
+		},
+		{
+			description:                "supports selected repositories",
+			config:                     []byte(`{"organization":"owner","visibility":"selected","selected_repositories":["a","b"]}`),
+			expectedVisibility:         "selected",
+			expectedSelectedRepoIDs:    github.SelectedRepoIDs{1, 2},
+			expectedGetRepositoryCalls: 2,
+		},
+		{
+			description:   "requires an organization",
+			config:        []byte(`{"repository":"owner/repository"}`),
+			expectedError: `parse config: "organization" must be defined`,
+		},
+		{
+			description:   "requires selected repositories for selected visibility",
+			config:        []byte(`{"organization":"owner","visibility":"selected"}`),
+			expectedError: `parse config: "selected_repositories" must be defined when visibility is "selected"`,
+		},
+		{
+			description:   "selected repositories requires selected visibility",
+			config:        []byte(`{"organization":"owner","selected_repositories":["a"]}`),
+			expectedError: `parse config: "selected_repositories" requires visibility "selected"`,
+		},
+		{
+			description:   "fails for invalid visibility",
+			config:        []byte(`{"organization":"owner","visibility":"public"}`),
+			expectedError: `parse config: invalid visibility: "public"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeApp := &githubfakes.FakeApp{}
+			fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: installationToken}, nil)
+
+			fakeOrganizationsAPI := &githubfakes.FakeOrganizationsAPI{}
+			fakeOrganizationsAPI.GetRepositoryStub = func(_ context.Context, _, repo string) (*github.Repository, *github.Response, error) {
+				return &github.Repository{ID: github.Int64(map[string]int64{"a": 1, "b": 2}[repo])}, nil, nil
+			}
+			fakeOrganizationsAPI.GetOrgPublicKeyReturns(&github.PublicKey{KeyID: github.String("key-id"), Key: github.String("")}, nil, nil)
+
+			store := secretstore.NewOrganizationStore(fakeApp,
+				secretstore.WithOrganizationsClientFactory(func(string) secretstore.OrganizationsAPI {
+					return fakeOrganizationsAPI
+				}),
+			)
+			assert.Equal(t, sidecred.GithubOrganizationSecrets, store.Type())
+
+			// Write twice to ensure that the visibility is set on every write.
+			for i := 0; i < 2; i++ {
+				path, err := store.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: "secret-value"}, tc.config)
+				if tc.expectedError != "" {
+					assert.EqualError(t, err, tc.expectedError)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, "TEAM_NAME_SECRET_NAME", path)
+
+				require.Equal(t, i+1, fakeOrganizationsAPI.CreateOrUpdateOrgSecretCallCount())
+				_, org, secret := fakeOrganizationsAPI.CreateOrUpdateOrgSecretArgsForCall(i)
+				assert.Equal(t, "owner", org)
+				assert.Equal(t, tc.expectedVisibility, secret.Visibility)
+				assert.Equal(t, tc.expectedSelectedRepoIDs, secret.SelectedRepositoryIDs)
+			}
+
+			// Organization tokens are not scoped to repositories, and repository IDs are cached.
+			_, owner, repositories, _ := fakeApp.CreateInstallationTokenArgsForCall(0)
+			assert.Equal(t, "owner", owner)
+			assert.Nil(t, repositories)
+			assert.Equal(t, tc.expectedGetRepositoryCalls, fakeOrganizationsAPI.GetRepositoryCallCount())
+
+			err := store.Delete(context.TODO(), "TEAM_NAME_SECRET_NAME", tc.config)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, fakeOrganizationsAPI.DeleteOrgSecretCallCount())
+		})
+	}
+}
+
+func TestCodespacesStore(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/owner/repository/codespaces/secrets/public-key":
+			w.Write([]byte(`{"key_id":"key-id","key":"` + base64.StdEncoding.EncodeToString(make([]byte, 32)) + `"}`)) //nolint:errcheck
+		case "PUT /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "key-id", body["key_id"])
+			assert.NotEmpty(t, body["encrypted_value"])
+			w.WriteHeader(http.StatusCreated)
+		case "GET /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME":
+			w.Write([]byte(`{"name":"TEAM_NAME_SECRET_NAME"}`)) //nolint:errcheck
+		case "DELETE /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+
+	fakeApp := &githubfakes.FakeApp{}
+	fakeApp.CreateInstallationTokenReturns(&githubapp.Token{InstallationToken: installationToken}, nil)
+
+	assert.Equal(t, sidecred.GithubCodespacesSecrets, secretstore.NewCodespacesStore(fakeApp).Type())
+
+	store := secretstore.NewStore(fakeApp, secretstore.WithActionsClientFactory(func(string) secretstore.ActionsAPI {
+		return secretstore.NewCodespacesClient(client)
+	}))
+	config := []byte(`{"repository":"owner/repository"}`)
+
+	path, err := store.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: "secret-value"}, config)
+	require.NoError(t, err)
+	assert.Equal(t, "TEAM_NAME_SECRET_NAME", path)
+
+	value, found, err := store.Read(context.TODO(), path, config)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, path, value)
+
+	require.NoError(t, store.Delete(context.TODO(), path, config))
+	require.NoError(t, store.Delete(context.TODO(), "MISSING", config), "missing secrets are ignored")
+
+	assert.Equal(t, []string{
+		"GET /repos/owner/repository/codespaces/secrets/public-key",
+		"PUT /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME",
+		"GET /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME",
+		"DELETE /repos/owner/repository/codespaces/secrets/TEAM_NAME_SECRET_NAME",
+		"DELETE /repos/owner/repository/codespaces/secrets/MISSING",
+	}, requests)
+}
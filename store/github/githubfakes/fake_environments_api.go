@@ -0,0 +1,475 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package githubfakes
+
+import (
+	"context"
+	"sync"
+
+	githuba "github.com/google/go-github/v45/github"
+	"github.com/telia-oss/sidecred/store/github"
+)
+
+type FakeEnvironmentsAPI struct {
+	CreateOrUpdateEnvSecretStub        func(context.Context, int, string, *githuba.EncryptedSecret) (*githuba.Response, error)
+	createOrUpdateEnvSecretMutex       sync.RWMutex
+	createOrUpdateEnvSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 *githuba.EncryptedSecret
+	}
+	createOrUpdateEnvSecretReturns struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	createOrUpdateEnvSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	DeleteEnvSecretStub        func(context.Context, int, string, string) (*githuba.Response, error)
+	deleteEnvSecretMutex       sync.RWMutex
+	deleteEnvSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}
+	deleteEnvSecretReturns struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	deleteEnvSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	GetEnvPublicKeyStub        func(context.Context, int, string) (*githuba.PublicKey, *githuba.Response, error)
+	getEnvPublicKeyMutex       sync.RWMutex
+	getEnvPublicKeyArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}
+	getEnvPublicKeyReturns struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}
+	getEnvPublicKeyReturnsOnCall map[int]struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}
+	GetEnvSecretStub        func(context.Context, int, string, string) (*githuba.Secret, *githuba.Response, error)
+	getEnvSecretMutex       sync.RWMutex
+	getEnvSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}
+	getEnvSecretReturns struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}
+	getEnvSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}
+	GetRepositoryStub        func(context.Context, string, string) (*githuba.Repository, *githuba.Response, error)
+	getRepositoryMutex       sync.RWMutex
+	getRepositoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getRepositoryReturns struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}
+	getRepositoryReturnsOnCall map[int]struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecret(arg1 context.Context, arg2 int, arg3 string, arg4 *githuba.EncryptedSecret) (*githuba.Response, error) {
+	fake.createOrUpdateEnvSecretMutex.Lock()
+	ret, specificReturn := fake.createOrUpdateEnvSecretReturnsOnCall[len(fake.createOrUpdateEnvSecretArgsForCall)]
+	fake.createOrUpdateEnvSecretArgsForCall = append(fake.createOrUpdateEnvSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 *githuba.EncryptedSecret
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.CreateOrUpdateEnvSecretStub
+	fakeReturns := fake.createOrUpdateEnvSecretReturns
+	fake.recordInvocation("CreateOrUpdateEnvSecret", []interface{}{arg1, arg2, arg3, arg4})
+	fake.createOrUpdateEnvSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecretCallCount() int {
+	fake.createOrUpdateEnvSecretMutex.RLock()
+	defer fake.createOrUpdateEnvSecretMutex.RUnlock()
+	return len(fake.createOrUpdateEnvSecretArgsForCall)
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecretCalls(stub func(context.Context, int, string, *githuba.EncryptedSecret) (*githuba.Response, error)) {
+	fake.createOrUpdateEnvSecretMutex.Lock()
+	defer fake.createOrUpdateEnvSecretMutex.Unlock()
+	fake.CreateOrUpdateEnvSecretStub = stub
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecretArgsForCall(i int) (context.Context, int, string, *githuba.EncryptedSecret) {
+	fake.createOrUpdateEnvSecretMutex.RLock()
+	defer fake.createOrUpdateEnvSecretMutex.RUnlock()
+	argsForCall := fake.createOrUpdateEnvSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecretReturns(result1 *githuba.Response, result2 error) {
+	fake.createOrUpdateEnvSecretMutex.Lock()
+	defer fake.createOrUpdateEnvSecretMutex.Unlock()
+	fake.CreateOrUpdateEnvSecretStub = nil
+	fake.createOrUpdateEnvSecretReturns = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnvironmentsAPI) CreateOrUpdateEnvSecretReturnsOnCall(i int, result1 *githuba.Response, result2 error) {
+	fake.createOrUpdateEnvSecretMutex.Lock()
+	defer fake.createOrUpdateEnvSecretMutex.Unlock()
+	fake.CreateOrUpdateEnvSecretStub = nil
+	if fake.createOrUpdateEnvSecretReturnsOnCall == nil {
+		fake.createOrUpdateEnvSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Response
+			result2 error
+		})
+	}
+	fake.createOrUpdateEnvSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecret(arg1 context.Context, arg2 int, arg3 string, arg4 string) (*githuba.Response, error) {
+	fake.deleteEnvSecretMutex.Lock()
+	ret, specificReturn := fake.deleteEnvSecretReturnsOnCall[len(fake.deleteEnvSecretArgsForCall)]
+	fake.deleteEnvSecretArgsForCall = append(fake.deleteEnvSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DeleteEnvSecretStub
+	fakeReturns := fake.deleteEnvSecretReturns
+	fake.recordInvocation("DeleteEnvSecret", []interface{}{arg1, arg2, arg3, arg4})
+	fake.deleteEnvSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecretCallCount() int {
+	fake.deleteEnvSecretMutex.RLock()
+	defer fake.deleteEnvSecretMutex.RUnlock()
+	return len(fake.deleteEnvSecretArgsForCall)
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecretCalls(stub func(context.Context, int, string, string) (*githuba.Response, error)) {
+	fake.deleteEnvSecretMutex.Lock()
+	defer fake.deleteEnvSecretMutex.Unlock()
+	fake.DeleteEnvSecretStub = stub
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecretArgsForCall(i int) (context.Context, int, string, string) {
+	fake.deleteEnvSecretMutex.RLock()
+	defer fake.deleteEnvSecretMutex.RUnlock()
+	argsForCall := fake.deleteEnvSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecretReturns(result1 *githuba.Response, result2 error) {
+	fake.deleteEnvSecretMutex.Lock()
+	defer fake.deleteEnvSecretMutex.Unlock()
+	fake.DeleteEnvSecretStub = nil
+	fake.deleteEnvSecretReturns = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnvironmentsAPI) DeleteEnvSecretReturnsOnCall(i int, result1 *githuba.Response, result2 error) {
+	fake.deleteEnvSecretMutex.Lock()
+	defer fake.deleteEnvSecretMutex.Unlock()
+	fake.DeleteEnvSecretStub = nil
+	if fake.deleteEnvSecretReturnsOnCall == nil {
+		fake.deleteEnvSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Response
+			result2 error
+		})
+	}
+	fake.deleteEnvSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKey(arg1 context.Context, arg2 int, arg3 string) (*githuba.PublicKey, *githuba.Response, error) {
+	fake.getEnvPublicKeyMutex.Lock()
+	ret, specificReturn := fake.getEnvPublicKeyReturnsOnCall[len(fake.getEnvPublicKeyArgsForCall)]
+	fake.getEnvPublicKeyArgsForCall = append(fake.getEnvPublicKeyArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetEnvPublicKeyStub
+	fakeReturns := fake.getEnvPublicKeyReturns
+	fake.recordInvocation("GetEnvPublicKey", []interface{}{arg1, arg2, arg3})
+	fake.getEnvPublicKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKeyCallCount() int {
+	fake.getEnvPublicKeyMutex.RLock()
+	defer fake.getEnvPublicKeyMutex.RUnlock()
+	return len(fake.getEnvPublicKeyArgsForCall)
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKeyCalls(stub func(context.Context, int, string) (*githuba.PublicKey, *githuba.Response, error)) {
+	fake.getEnvPublicKeyMutex.Lock()
+	defer fake.getEnvPublicKeyMutex.Unlock()
+	fake.GetEnvPublicKeyStub = stub
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKeyArgsForCall(i int) (context.Context, int, string) {
+	fake.getEnvPublicKeyMutex.RLock()
+	defer fake.getEnvPublicKeyMutex.RUnlock()
+	argsForCall := fake.getEnvPublicKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKeyReturns(result1 *githuba.PublicKey, result2 *githuba.Response, result3 error) {
+	fake.getEnvPublicKeyMutex.Lock()
+	defer fake.getEnvPublicKeyMutex.Unlock()
+	fake.GetEnvPublicKeyStub = nil
+	fake.getEnvPublicKeyReturns = struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvPublicKeyReturnsOnCall(i int, result1 *githuba.PublicKey, result2 *githuba.Response, result3 error) {
+	fake.getEnvPublicKeyMutex.Lock()
+	defer fake.getEnvPublicKeyMutex.Unlock()
+	fake.GetEnvPublicKeyStub = nil
+	if fake.getEnvPublicKeyReturnsOnCall == nil {
+		fake.getEnvPublicKeyReturnsOnCall = make(map[int]struct {
+			result1 *githuba.PublicKey
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getEnvPublicKeyReturnsOnCall[i] = struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecret(arg1 context.Context, arg2 int, arg3 string, arg4 string) (*githuba.Secret, *githuba.Response, error) {
+	fake.getEnvSecretMutex.Lock()
+	ret, specificReturn := fake.getEnvSecretReturnsOnCall[len(fake.getEnvSecretArgsForCall)]
+	fake.getEnvSecretArgsForCall = append(fake.getEnvSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetEnvSecretStub
+	fakeReturns := fake.getEnvSecretReturns
+	fake.recordInvocation("GetEnvSecret", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getEnvSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecretCallCount() int {
+	fake.getEnvSecretMutex.RLock()
+	defer fake.getEnvSecretMutex.RUnlock()
+	return len(fake.getEnvSecretArgsForCall)
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecretCalls(stub func(context.Context, int, string, string) (*githuba.Secret, *githuba.Response, error)) {
+	fake.getEnvSecretMutex.Lock()
+	defer fake.getEnvSecretMutex.Unlock()
+	fake.GetEnvSecretStub = stub
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecretArgsForCall(i int) (context.Context, int, string, string) {
+	fake.getEnvSecretMutex.RLock()
+	defer fake.getEnvSecretMutex.RUnlock()
+	argsForCall := fake.getEnvSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecretReturns(result1 *githuba.Secret, result2 *githuba.Response, result3 error) {
+	fake.getEnvSecretMutex.Lock()
+	defer fake.getEnvSecretMutex.Unlock()
+	fake.GetEnvSecretStub = nil
+	fake.getEnvSecretReturns = struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) GetEnvSecretReturnsOnCall(i int, result1 *githuba.Secret, result2 *githuba.Response, result3 error) {
+	fake.getEnvSecretMutex.Lock()
+	defer fake.getEnvSecretMutex.Unlock()
+	fake.GetEnvSecretStub = nil
+	if fake.getEnvSecretReturnsOnCall == nil {
+		fake.getEnvSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Secret
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getEnvSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepository(arg1 context.Context, arg2 string, arg3 string) (*githuba.Repository, *githuba.Response, error) {
+	fake.getRepositoryMutex.Lock()
+	ret, specificReturn := fake.getRepositoryReturnsOnCall[len(fake.getRepositoryArgsForCall)]
+	fake.getRepositoryArgsForCall = append(fake.getRepositoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetRepositoryStub
+	fakeReturns := fake.getRepositoryReturns
+	fake.recordInvocation("GetRepository", []interface{}{arg1, arg2, arg3})
+	fake.getRepositoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepositoryCallCount() int {
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	return len(fake.getRepositoryArgsForCall)
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepositoryCalls(stub func(context.Context, string, string) (*githuba.Repository, *githuba.Response, error)) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = stub
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepositoryArgsForCall(i int) (context.Context, string, string) {
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	argsForCall := fake.getRepositoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepositoryReturns(result1 *githuba.Repository, result2 *githuba.Response, result3 error) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = nil
+	fake.getRepositoryReturns = struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) GetRepositoryReturnsOnCall(i int, result1 *githuba.Repository, result2 *githuba.Response, result3 error) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = nil
+	if fake.getRepositoryReturnsOnCall == nil {
+		fake.getRepositoryReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Repository
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getRepositoryReturnsOnCall[i] = struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeEnvironmentsAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createOrUpdateEnvSecretMutex.RLock()
+	defer fake.createOrUpdateEnvSecretMutex.RUnlock()
+	fake.deleteEnvSecretMutex.RLock()
+	defer fake.deleteEnvSecretMutex.RUnlock()
+	fake.getEnvPublicKeyMutex.RLock()
+	defer fake.getEnvPublicKeyMutex.RUnlock()
+	fake.getEnvSecretMutex.RLock()
+	defer fake.getEnvSecretMutex.RUnlock()
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeEnvironmentsAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ github.EnvironmentsAPI = new(FakeEnvironmentsAPI)
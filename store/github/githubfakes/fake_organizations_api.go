@@ -0,0 +1,467 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package githubfakes
+
+import (
+	"context"
+	"sync"
+
+	githuba "github.com/google/go-github/v45/github"
+	"github.com/telia-oss/sidecred/store/github"
+)
+
+type FakeOrganizationsAPI struct {
+	CreateOrUpdateOrgSecretStub        func(context.Context, string, *githuba.EncryptedSecret) (*githuba.Response, error)
+	createOrUpdateOrgSecretMutex       sync.RWMutex
+	createOrUpdateOrgSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *githuba.EncryptedSecret
+	}
+	createOrUpdateOrgSecretReturns struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	createOrUpdateOrgSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	DeleteOrgSecretStub        func(context.Context, string, string) (*githuba.Response, error)
+	deleteOrgSecretMutex       sync.RWMutex
+	deleteOrgSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	deleteOrgSecretReturns struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	deleteOrgSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Response
+		result2 error
+	}
+	GetOrgPublicKeyStub        func(context.Context, string) (*githuba.PublicKey, *githuba.Response, error)
+	getOrgPublicKeyMutex       sync.RWMutex
+	getOrgPublicKeyArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getOrgPublicKeyReturns struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}
+	getOrgPublicKeyReturnsOnCall map[int]struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}
+	GetOrgSecretStub        func(context.Context, string, string) (*githuba.Secret, *githuba.Response, error)
+	getOrgSecretMutex       sync.RWMutex
+	getOrgSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getOrgSecretReturns struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}
+	getOrgSecretReturnsOnCall map[int]struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}
+	GetRepositoryStub        func(context.Context, string, string) (*githuba.Repository, *githuba.Response, error)
+	getRepositoryMutex       sync.RWMutex
+	getRepositoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getRepositoryReturns struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}
+	getRepositoryReturnsOnCall map[int]struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecret(arg1 context.Context, arg2 string, arg3 *githuba.EncryptedSecret) (*githuba.Response, error) {
+	fake.createOrUpdateOrgSecretMutex.Lock()
+	ret, specificReturn := fake.createOrUpdateOrgSecretReturnsOnCall[len(fake.createOrUpdateOrgSecretArgsForCall)]
+	fake.createOrUpdateOrgSecretArgsForCall = append(fake.createOrUpdateOrgSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *githuba.EncryptedSecret
+	}{arg1, arg2, arg3})
+	stub := fake.CreateOrUpdateOrgSecretStub
+	fakeReturns := fake.createOrUpdateOrgSecretReturns
+	fake.recordInvocation("CreateOrUpdateOrgSecret", []interface{}{arg1, arg2, arg3})
+	fake.createOrUpdateOrgSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecretCallCount() int {
+	fake.createOrUpdateOrgSecretMutex.RLock()
+	defer fake.createOrUpdateOrgSecretMutex.RUnlock()
+	return len(fake.createOrUpdateOrgSecretArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecretCalls(stub func(context.Context, string, *githuba.EncryptedSecret) (*githuba.Response, error)) {
+	fake.createOrUpdateOrgSecretMutex.Lock()
+	defer fake.createOrUpdateOrgSecretMutex.Unlock()
+	fake.CreateOrUpdateOrgSecretStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecretArgsForCall(i int) (context.Context, string, *githuba.EncryptedSecret) {
+	fake.createOrUpdateOrgSecretMutex.RLock()
+	defer fake.createOrUpdateOrgSecretMutex.RUnlock()
+	argsForCall := fake.createOrUpdateOrgSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecretReturns(result1 *githuba.Response, result2 error) {
+	fake.createOrUpdateOrgSecretMutex.Lock()
+	defer fake.createOrUpdateOrgSecretMutex.Unlock()
+	fake.CreateOrUpdateOrgSecretStub = nil
+	fake.createOrUpdateOrgSecretReturns = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrganizationsAPI) CreateOrUpdateOrgSecretReturnsOnCall(i int, result1 *githuba.Response, result2 error) {
+	fake.createOrUpdateOrgSecretMutex.Lock()
+	defer fake.createOrUpdateOrgSecretMutex.Unlock()
+	fake.CreateOrUpdateOrgSecretStub = nil
+	if fake.createOrUpdateOrgSecretReturnsOnCall == nil {
+		fake.createOrUpdateOrgSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Response
+			result2 error
+		})
+	}
+	fake.createOrUpdateOrgSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecret(arg1 context.Context, arg2 string, arg3 string) (*githuba.Response, error) {
+	fake.deleteOrgSecretMutex.Lock()
+	ret, specificReturn := fake.deleteOrgSecretReturnsOnCall[len(fake.deleteOrgSecretArgsForCall)]
+	fake.deleteOrgSecretArgsForCall = append(fake.deleteOrgSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteOrgSecretStub
+	fakeReturns := fake.deleteOrgSecretReturns
+	fake.recordInvocation("DeleteOrgSecret", []interface{}{arg1, arg2, arg3})
+	fake.deleteOrgSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecretCallCount() int {
+	fake.deleteOrgSecretMutex.RLock()
+	defer fake.deleteOrgSecretMutex.RUnlock()
+	return len(fake.deleteOrgSecretArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecretCalls(stub func(context.Context, string, string) (*githuba.Response, error)) {
+	fake.deleteOrgSecretMutex.Lock()
+	defer fake.deleteOrgSecretMutex.Unlock()
+	fake.DeleteOrgSecretStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecretArgsForCall(i int) (context.Context, string, string) {
+	fake.deleteOrgSecretMutex.RLock()
+	defer fake.deleteOrgSecretMutex.RUnlock()
+	argsForCall := fake.deleteOrgSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecretReturns(result1 *githuba.Response, result2 error) {
+	fake.deleteOrgSecretMutex.Lock()
+	defer fake.deleteOrgSecretMutex.Unlock()
+	fake.DeleteOrgSecretStub = nil
+	fake.deleteOrgSecretReturns = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrganizationsAPI) DeleteOrgSecretReturnsOnCall(i int, result1 *githuba.Response, result2 error) {
+	fake.deleteOrgSecretMutex.Lock()
+	defer fake.deleteOrgSecretMutex.Unlock()
+	fake.DeleteOrgSecretStub = nil
+	if fake.deleteOrgSecretReturnsOnCall == nil {
+		fake.deleteOrgSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Response
+			result2 error
+		})
+	}
+	fake.deleteOrgSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Response
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKey(arg1 context.Context, arg2 string) (*githuba.PublicKey, *githuba.Response, error) {
+	fake.getOrgPublicKeyMutex.Lock()
+	ret, specificReturn := fake.getOrgPublicKeyReturnsOnCall[len(fake.getOrgPublicKeyArgsForCall)]
+	fake.getOrgPublicKeyArgsForCall = append(fake.getOrgPublicKeyArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetOrgPublicKeyStub
+	fakeReturns := fake.getOrgPublicKeyReturns
+	fake.recordInvocation("GetOrgPublicKey", []interface{}{arg1, arg2})
+	fake.getOrgPublicKeyMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKeyCallCount() int {
+	fake.getOrgPublicKeyMutex.RLock()
+	defer fake.getOrgPublicKeyMutex.RUnlock()
+	return len(fake.getOrgPublicKeyArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKeyCalls(stub func(context.Context, string) (*githuba.PublicKey, *githuba.Response, error)) {
+	fake.getOrgPublicKeyMutex.Lock()
+	defer fake.getOrgPublicKeyMutex.Unlock()
+	fake.GetOrgPublicKeyStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKeyArgsForCall(i int) (context.Context, string) {
+	fake.getOrgPublicKeyMutex.RLock()
+	defer fake.getOrgPublicKeyMutex.RUnlock()
+	argsForCall := fake.getOrgPublicKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKeyReturns(result1 *githuba.PublicKey, result2 *githuba.Response, result3 error) {
+	fake.getOrgPublicKeyMutex.Lock()
+	defer fake.getOrgPublicKeyMutex.Unlock()
+	fake.GetOrgPublicKeyStub = nil
+	fake.getOrgPublicKeyReturns = struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgPublicKeyReturnsOnCall(i int, result1 *githuba.PublicKey, result2 *githuba.Response, result3 error) {
+	fake.getOrgPublicKeyMutex.Lock()
+	defer fake.getOrgPublicKeyMutex.Unlock()
+	fake.GetOrgPublicKeyStub = nil
+	if fake.getOrgPublicKeyReturnsOnCall == nil {
+		fake.getOrgPublicKeyReturnsOnCall = make(map[int]struct {
+			result1 *githuba.PublicKey
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getOrgPublicKeyReturnsOnCall[i] = struct {
+		result1 *githuba.PublicKey
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecret(arg1 context.Context, arg2 string, arg3 string) (*githuba.Secret, *githuba.Response, error) {
+	fake.getOrgSecretMutex.Lock()
+	ret, specificReturn := fake.getOrgSecretReturnsOnCall[len(fake.getOrgSecretArgsForCall)]
+	fake.getOrgSecretArgsForCall = append(fake.getOrgSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetOrgSecretStub
+	fakeReturns := fake.getOrgSecretReturns
+	fake.recordInvocation("GetOrgSecret", []interface{}{arg1, arg2, arg3})
+	fake.getOrgSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecretCallCount() int {
+	fake.getOrgSecretMutex.RLock()
+	defer fake.getOrgSecretMutex.RUnlock()
+	return len(fake.getOrgSecretArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecretCalls(stub func(context.Context, string, string) (*githuba.Secret, *githuba.Response, error)) {
+	fake.getOrgSecretMutex.Lock()
+	defer fake.getOrgSecretMutex.Unlock()
+	fake.GetOrgSecretStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecretArgsForCall(i int) (context.Context, string, string) {
+	fake.getOrgSecretMutex.RLock()
+	defer fake.getOrgSecretMutex.RUnlock()
+	argsForCall := fake.getOrgSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecretReturns(result1 *githuba.Secret, result2 *githuba.Response, result3 error) {
+	fake.getOrgSecretMutex.Lock()
+	defer fake.getOrgSecretMutex.Unlock()
+	fake.GetOrgSecretStub = nil
+	fake.getOrgSecretReturns = struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetOrgSecretReturnsOnCall(i int, result1 *githuba.Secret, result2 *githuba.Response, result3 error) {
+	fake.getOrgSecretMutex.Lock()
+	defer fake.getOrgSecretMutex.Unlock()
+	fake.GetOrgSecretStub = nil
+	if fake.getOrgSecretReturnsOnCall == nil {
+		fake.getOrgSecretReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Secret
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getOrgSecretReturnsOnCall[i] = struct {
+		result1 *githuba.Secret
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetRepository(arg1 context.Context, arg2 string, arg3 string) (*githuba.Repository, *githuba.Response, error) {
+	fake.getRepositoryMutex.Lock()
+	ret, specificReturn := fake.getRepositoryReturnsOnCall[len(fake.getRepositoryArgsForCall)]
+	fake.getRepositoryArgsForCall = append(fake.getRepositoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetRepositoryStub
+	fakeReturns := fake.getRepositoryReturns
+	fake.recordInvocation("GetRepository", []interface{}{arg1, arg2, arg3})
+	fake.getRepositoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeOrganizationsAPI) GetRepositoryCallCount() int {
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	return len(fake.getRepositoryArgsForCall)
+}
+
+func (fake *FakeOrganizationsAPI) GetRepositoryCalls(stub func(context.Context, string, string) (*githuba.Repository, *githuba.Response, error)) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = stub
+}
+
+func (fake *FakeOrganizationsAPI) GetRepositoryArgsForCall(i int) (context.Context, string, string) {
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	argsForCall := fake.getRepositoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeOrganizationsAPI) GetRepositoryReturns(result1 *githuba.Repository, result2 *githuba.Response, result3 error) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = nil
+	fake.getRepositoryReturns = struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) GetRepositoryReturnsOnCall(i int, result1 *githuba.Repository, result2 *githuba.Response, result3 error) {
+	fake.getRepositoryMutex.Lock()
+	defer fake.getRepositoryMutex.Unlock()
+	fake.GetRepositoryStub = nil
+	if fake.getRepositoryReturnsOnCall == nil {
+		fake.getRepositoryReturnsOnCall = make(map[int]struct {
+			result1 *githuba.Repository
+			result2 *githuba.Response
+			result3 error
+		})
+	}
+	fake.getRepositoryReturnsOnCall[i] = struct {
+		result1 *githuba.Repository
+		result2 *githuba.Response
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeOrganizationsAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createOrUpdateOrgSecretMutex.RLock()
+	defer fake.createOrUpdateOrgSecretMutex.RUnlock()
+	fake.deleteOrgSecretMutex.RLock()
+	defer fake.deleteOrgSecretMutex.RUnlock()
+	fake.getOrgPublicKeyMutex.RLock()
+	defer fake.getOrgPublicKeyMutex.RUnlock()
+	fake.getOrgSecretMutex.RLock()
+	defer fake.getOrgSecretMutex.RUnlock()
+	fake.getRepositoryMutex.RLock()
+	defer fake.getRepositoryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeOrganizationsAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ github.OrganizationsAPI = new(FakeOrganizationsAPI)
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/telia-oss/githubapp"
+
+	"github.com/telia-oss/sidecred"
+)
+
+// Visibility of organization secrets.
+const (
+	VisibilityAll      = "all"
+	VisibilityPrivate  = "private"
+	VisibilitySelected = "selected"
+)
+
+// NewOrganizationStore creates a new sidecred.SecretStore using Github organization secrets.
+func NewOrganizationStore(app App, options ...Option) sidecred.SecretStore {
+	options = append(options, forStoreType(sidecred.GithubOrganizationSecrets))
+
+	return NewStore(app, options...)
+}
+
+// organizationsClient implements OrganizationsAPI using the Github API.
+type organizationsClient struct {
+	*github.Client
+}
+
+func (c *organizationsClient) GetRepository(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error) {
+	return c.Repositories.Get(ctx, owner, repo)
+}
+
+func (c *organizationsClient) GetOrgPublicKey(ctx context.Context, org string) (*github.PublicKey, *github.Response, error) {
+	return c.Actions.GetOrgPublicKey(ctx, org)
+}
+
+func (c *organizationsClient) CreateOrUpdateOrgSecret(ctx context.Context, org string, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	return c.Actions.CreateOrUpdateOrgSecret(ctx, org, eSecret)
+}
+
+func (c *organizationsClient) GetOrgSecret(ctx context.Context, org, name string) (*github.Secret, *github.Response, error) {
+	return c.Actions.GetOrgSecret(ctx, org, name)
+}
+
+func (c *organizationsClient) DeleteOrgSecret(ctx context.Context, org, name string) (*github.Response, error) {
+	return c.Actions.DeleteOrgSecret(ctx, org, name)
+}
+
+func newOrganizationsClient(token string) OrganizationsAPI {
+	return &organizationsClient{Client: githubapp.NewInstallationClient(token).V3}
+}
+
+// organizationSecretsClient implements secretsClient for organization secrets.
+type organizationSecretsClient struct {
+	api                   OrganizationsAPI
+	organization          string
+	visibility            string
+	selectedRepositoryIDs []int64
+}
+
+func (c *organizationSecretsClient) ID() string {
+	return c.organization
+}
+
+func (c *organizationSecretsClient) GetPublicKey(ctx context.Context) (*github.PublicKey, *github.Response, error) {
+	return c.api.GetOrgPublicKey(ctx, c.organization)
+}
+
+// CreateOrUpdateSecret sets the visibility (and selected repositories) on every write, which
+// keeps the secret in sync with the config.
+func (c *organizationSecretsClient) CreateOrUpdateSecret(ctx context.Context, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	eSecret.Visibility = c.visibility
+	if c.visibility == VisibilitySelected {
+		eSecret.SelectedRepositoryIDs = c.selectedRepositoryIDs
+	}
+	return c.api.CreateOrUpdateOrgSecret(ctx, c.organization, eSecret)
+}
+
+func (c *organizationSecretsClient) GetSecret(ctx context.Context, name string) (*github.Secret, *github.Response, error) {
+	return c.api.GetOrgSecret(ctx, c.organization, name)
+}
+
+func (c *organizationSecretsClient) DeleteSecret(ctx context.Context, name string) (*github.Response, error) {
+	return c.api.DeleteOrgSecret(ctx, c.organization, name)
+}
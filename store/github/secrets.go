@@ -0,0 +1,44 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v45/github"
+)
+
+// secretsClient manages secrets for a single target (e.g. a repository or an environment), and
+// allows the store to support different types of Github secrets.
+type secretsClient interface {
+	// ID uniquely identifies the target, and is used to cache public keys.
+	ID() string
+	GetPublicKey(ctx context.Context) (*github.PublicKey, *github.Response, error)
+	CreateOrUpdateSecret(ctx context.Context, eSecret *github.EncryptedSecret) (*github.Response, error)
+	GetSecret(ctx context.Context, name string) (*github.Secret, *github.Response, error)
+	DeleteSecret(ctx context.Context, name string) (*github.Response, error)
+}
+
+// repositorySecretsClient implements secretsClient for repository secrets.
+type repositorySecretsClient struct {
+	api               ActionsAPI
+	owner, repository string
+}
+
+func (c *repositorySecretsClient) ID() string {
+	return c.owner + "/" + c.repository
+}
+
+func (c *repositorySecretsClient) GetPublicKey(ctx context.Context) (*github.PublicKey, *github.Response, error) {
+	return c.api.GetRepoPublicKey(ctx, c.owner, c.repository)
+}
+
+func (c *repositorySecretsClient) CreateOrUpdateSecret(ctx context.Context, eSecret *github.EncryptedSecret) (*github.Response, error) {
+	return c.api.CreateOrUpdateRepoSecret(ctx, c.owner, c.repository, eSecret)
+}
+
+func (c *repositorySecretsClient) GetSecret(ctx context.Context, name string) (*github.Secret, *github.Response, error) {
+	return c.api.GetRepoSecret(ctx, c.owner, c.repository, name)
+}
+
+func (c *repositorySecretsClient) DeleteSecret(ctx context.Context, name string) (*github.Response, error) {
+	return c.api.DeleteRepoSecret(ctx, c.owner, c.repository, name)
+}
@@ -0,0 +1,199 @@
+// Package gitlab implements sidecred.SecretStore on top of GitLab CI/CD variables.
+package gitlab
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/internal/gitlab"
+)
+
+// illegalCharactersRegex matches characters that are not allowed in the key of a variable.
+var illegalCharactersRegex = regexp.MustCompile("[^a-zA-Z0-9_]+")
+
+// maskableValueRegex matches values that can be masked by GitLab: a single line of at least 8 characters
+// from the Base64 alphabet (RFC4648, including "-" and "_" from the URL safe alphabet), and "@", ":", "." or "~".
+var maskableValueRegex = regexp.MustCompile(`^[a-zA-Z0-9+/=@:.~_-]{8,}$`)
+
+// New creates a new sidecred.SecretStore using GitLab CI/CD variables.
+func New(client GitlabAPI, options ...option) sidecred.SecretStore {
+	s := &store{
+		client:         client,
+		secretTemplate: "{{ .Namespace }}_{{ .Name }}",
+	}
+	for _, optionFunc := range options {
+		optionFunc(s)
+	}
+	return s
+}
+
+type option func(*store)
+
+// WithSecretTemplate sets the variable key template when instantiating a new store.
+func WithSecretTemplate(t string) option {
+	return func(s *store) {
+		s.secretTemplate = t
+	}
+}
+
+type store struct {
+	client         GitlabAPI
+	secretTemplate string
+}
+
+// config that can be passed to the Configure method of this store.
+//
+// Either project or group must be defined (ID or full path, e.g. "group/project"). Variables are
+// masked by default, and the environment scope defaults to all environments ("*"). Since GitLab cannot
+// mask all values, writing a value that cannot be masked fails unless masked is explicitly set to false.
+type config struct {
+	SecretTemplate   string `json:"secret_template"`
+	Project          string `json:"project"`
+	Group            string `json:"group"`
+	Masked           *bool  `json:"masked"`
+	Protected        bool   `json:"protected"`
+	EnvironmentScope string `json:"environment_scope"`
+
+	// Fields populated when the config is parsed
+	resource string
+}
+
+// Type implements sidecred.SecretStore.
+func (s *store) Type() sidecred.StoreType {
+	return sidecred.GitlabVariables
+}
+
+// Write implements sidecred.SecretStore.
+func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Credential, config json.RawMessage) (string, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("parse config: %s", err)
+	}
+	key, err := sidecred.BuildSecretTemplate(c.SecretTemplate, namespace, secret.Name)
+	if err != nil {
+		return "", fmt.Errorf("build secret key: %s", err)
+	}
+	key = illegalCharactersRegex.ReplaceAllString(key, "_")
+
+	// Fail instead of writing an unmasked variable, since the value could be printed in job logs.
+	masked := c.Masked == nil || *c.Masked
+	if masked && !maskableValueRegex.MatchString(secret.Value) {
+		return "", fmt.Errorf("variable %q cannot be masked (set %q to false to write it unmasked)", key, "masked")
+	}
+
+	// Variables are raw (not expanded), since credentials can contain "$".
+	variable := &gitlab.Variable{
+		Key:              key,
+		Value:            secret.Value,
+		VariableType:     "env_var",
+		Protected:        c.Protected,
+		Masked:           masked,
+		Raw:              true,
+		EnvironmentScope: c.EnvironmentScope,
+	}
+	existing, err := s.client.GetVariable(ctx, c.resource, key, c.EnvironmentScope)
+	if err != nil {
+		return "", fmt.Errorf("get variable: %s", err)
+	}
+	if existing == nil {
+		if _, err := s.client.CreateVariable(ctx, c.resource, variable); err != nil {
+			return "", fmt.Errorf("create variable: %s", err)
+		}
+	} else {
+		if _, err := s.client.UpdateVariable(ctx, c.resource, variable); err != nil {
+			return "", fmt.Errorf("update variable: %s", err)
+		}
+	}
+	return buildPath(key, c.EnvironmentScope), nil
+}
+
+// Read implements sidecred.SecretStore.
+func (s *store) Read(ctx context.Context, path string, config json.RawMessage) (string, bool, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", false, fmt.Errorf("parse config: %s", err)
+	}
+	key, environmentScope, err := parsePath(path)
+	if err != nil {
+		return "", false, err
+	}
+	variable, err := s.client.GetVariable(ctx, c.resource, key, environmentScope)
+	if err != nil {
+		return "", false, fmt.Errorf("get variable: %s", err)
+	}
+	if variable == nil {
+		return "", false, nil
+	}
+	return variable.Value, true, nil
+}
+
+// Delete implements sidecred.SecretStore.
+func (s *store) Delete(ctx context.Context, path string, config json.RawMessage) error {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse config: %s", err)
+	}
+	key, environmentScope, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if err := s.client.DeleteVariable(ctx, c.resource, key, environmentScope); err != nil && !gitlab.IsNotFound(err) {
+		return fmt.Errorf("delete variable: %s", err)
+	}
+	return nil
+}
+
+// parseConfig parses and validates the config.
+func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
+	c := &config{}
+	if err := sidecred.UnmarshalConfig(raw, &c); err != nil {
+		return nil, err
+	}
+	switch {
+	case c.Project != "" && c.Group != "":
+		return nil, fmt.Errorf("only one of %q or %q can be defined", "project", "group")
+	case c.Project != "":
+		c.resource = gitlab.Project(c.Project)
+	case c.Group != "":
+		c.resource = gitlab.Group(c.Group)
+	default:
+		return nil, fmt.Errorf("%q or %q must be defined", "project", "group")
+	}
+	if c.SecretTemplate == "" {
+		c.SecretTemplate = s.secretTemplate
+	}
+	if c.EnvironmentScope == "" {
+		c.EnvironmentScope = "*"
+	}
+	return c, nil
+}
+
+// buildPath returns the path (reference) to a variable. The environment scope is included since
+// the same key can be used for multiple scopes.
+func buildPath(key, environmentScope string) string {
+	return key + ":" + environmentScope
+}
+
+// parsePath parses paths returned by buildPath.
+func parsePath(path string) (key, environmentScope string, err error) {
+	parts := strings.SplitN(path, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid path: %q", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// GitlabAPI wraps the GitLab API for CI/CD variables.
+//
+//counterfeiter:generate . GitlabAPI
+type GitlabAPI interface {
+	GetVariable(ctx context.Context, resource, key, environmentScope string) (*gitlab.Variable, error)
+	CreateVariable(ctx context.Context, resource string, variable *gitlab.Variable) (*gitlab.Variable, error)
+	UpdateVariable(ctx context.Context, resource string, variable *gitlab.Variable) (*gitlab.Variable, error)
+	DeleteVariable(ctx context.Context, resource, key, environmentScope string) error
+}
@@ -0,0 +1,209 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/internal/gitlab"
+	gitlabstore "github.com/telia-oss/sidecred/store/gitlab"
+	"github.com/telia-oss/sidecred/store/gitlab/gitlabfakes"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		description         string
+		config              json.RawMessage
+		value               string
+		existing            *gitlab.Variable
+		expectedPath        string
+		expectedResource    string
+		expectedVariable    *gitlab.Variable
+		expectedCreateCalls int
+		expectedUpdateCalls int
+		expectedError       string
+	}{
+		{
+			description:      "gitlab store works",
+			config:           []byte(`{"project":"group/project"}`),
+			expectedPath:     "team_name_secret_name:*",
+			expectedResource: "projects/group%2Fproject",
+			expectedVariable: &gitlab.Variable{
+				Key:              "team_name_secret_name",
+				Value:            "secret-value",
+				VariableType:     "env_var",
+				Masked:           true,
+				Raw:              true,
+				EnvironmentScope: "*",
+			},
+			expectedCreateCalls: 1,
+		},
+		{
+			description:      "supports config",
+			config:           []byte(`{"group":"group","secret_template":"SIDECRED_{{ .Name }}","masked":false,"protected":true,"environment_scope":"production"}`),
+			expectedPath:     "SIDECRED_secret_name:production",
+			expectedResource: "groups/group",
+			expectedVariable: &gitlab.Variable{
+				Key:              "SIDECRED_secret_name",
+				Value:            "secret-value",
+				VariableType:     "env_var",
+				Protected:        true,
+				Raw:              true,
+				EnvironmentScope: "production",
+			},
+			expectedCreateCalls: 1,
+		},
+		{
+			description:      "updates existing variables",
+			config:           []byte(`{"project":"42"}`),
+			existing:         &gitlab.Variable{Key: "team_name_secret_name", Value: "old-value"},
+			expectedPath:     "team_name_secret_name:*",
+			expectedResource: "projects/42",
+			expectedVariable: &gitlab.Variable{
+				Key:              "team_name_secret_name",
+				Value:            "secret-value",
+				VariableType:     "env_var",
+				Masked:           true,
+				Raw:              true,
+				EnvironmentScope: "*",
+			},
+			expectedUpdateCalls: 1,
+		},
+		{
+			description:   "fails if the value cannot be masked",
+			config:        []byte(`{"project":"42"}`),
+			value:         "p@ss!word#%",
+			expectedError: `variable "team_name_secret_name" cannot be masked (set "masked" to false to write it unmasked)`,
+		},
+		{
+			description:   "fails for multi-line values",
+			config:        []byte(`{"project":"42","masked":true}`),
+			value:         "multi\nline\nvalue",
+			expectedError: `variable "team_name_secret_name" cannot be masked (set "masked" to false to write it unmasked)`,
+		},
+		{
+			description:      "writes unmasked variables when masking is disabled",
+			config:           []byte(`{"project":"42","masked":false}`),
+			value:            "short",
+			expectedPath:     "team_name_secret_name:*",
+			expectedResource: "projects/42",
+			expectedVariable: &gitlab.Variable{
+				Key:              "team_name_secret_name",
+				Value:            "short",
+				VariableType:     "env_var",
+				Raw:              true,
+				EnvironmentScope: "*",
+			},
+			expectedCreateCalls: 1,
+		},
+		{
+			description:      "masks values from the url safe base64 alphabet",
+			config:           []byte(`{"project":"42"}`),
+			value:            "abc_DEF-123~@:.",
+			expectedPath:     "team_name_secret_name:*",
+			expectedResource: "projects/42",
+			expectedVariable: &gitlab.Variable{
+				Key:              "team_name_secret_name",
+				Value:            "abc_DEF-123~@:.",
+				VariableType:     "env_var",
+				Masked:           true,
+				Raw:              true,
+				EnvironmentScope: "*",
+			},
+			expectedCreateCalls: 1,
+		},
+		{
+			description:   "requires a project or group",
+			expectedError: `parse config: "project" or "group" must be defined`,
+		},
+		{
+			description:   "does not allow both project and group",
+			config:        []byte(`{"project":"group/project","group":"group"}`),
+			expectedError: `parse config: only one of "project" or "group" can be defined`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeGitlabAPI := &gitlabfakes.FakeGitlabAPI{}
+			fakeGitlabAPI.GetVariableReturns(tc.existing, nil)
+
+			value := tc.value
+			if value == "" {
+				value = "secret-value"
+			}
+			path, err := gitlabstore.New(fakeGitlabAPI).Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: value}, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+			assert.Equal(t, tc.expectedCreateCalls, fakeGitlabAPI.CreateVariableCallCount(), "create calls")
+			assert.Equal(t, tc.expectedUpdateCalls, fakeGitlabAPI.UpdateVariableCallCount(), "update calls")
+
+			var resource string
+			var variable *gitlab.Variable
+			if tc.expectedCreateCalls > 0 {
+				_, resource, variable = fakeGitlabAPI.CreateVariableArgsForCall(0)
+			} else {
+				_, resource, variable = fakeGitlabAPI.UpdateVariableArgsForCall(0)
+			}
+			assert.Equal(t, tc.expectedResource, resource)
+			assert.Equal(t, tc.expectedVariable, variable)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		description   string
+		path          string
+		deleteError   error
+		expectedKey   string
+		expectedScope string
+		expectedError string
+	}{
+		{
+			description:   "gitlab store works",
+			path:          "TEAM_NAME_SECRET_NAME:production",
+			expectedKey:   "TEAM_NAME_SECRET_NAME",
+			expectedScope: "production",
+		},
+		{
+			description:   "ignores variables that do not exist",
+			path:          "TEAM_NAME_SECRET_NAME:*",
+			deleteError:   &gitlab.ResponseError{StatusCode: 404},
+			expectedKey:   "TEAM_NAME_SECRET_NAME",
+			expectedScope: "*",
+		},
+		{
+			description:   "fails for invalid paths",
+			path:          "TEAM_NAME_SECRET_NAME",
+			expectedError: `invalid path: "TEAM_NAME_SECRET_NAME"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			fakeGitlabAPI := &gitlabfakes.FakeGitlabAPI{}
+			fakeGitlabAPI.DeleteVariableReturns(tc.deleteError)
+
+			err := gitlabstore.New(fakeGitlabAPI).Delete(context.TODO(), tc.path, []byte(`{"project":"group/project"}`))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, fakeGitlabAPI.DeleteVariableCallCount())
+			_, resource, key, scope := fakeGitlabAPI.DeleteVariableArgsForCall(0)
+			assert.Equal(t, "projects/group%2Fproject", resource)
+			assert.Equal(t, tc.expectedKey, key)
+			assert.Equal(t, tc.expectedScope, scope)
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package gitlabfakes
+
+import (
+	"context"
+	"sync"
+
+	gitlaba "github.com/telia-oss/sidecred/internal/gitlab"
+	"github.com/telia-oss/sidecred/store/gitlab"
+)
+
+type FakeGitlabAPI struct {
+	CreateVariableStub        func(context.Context, string, *gitlaba.Variable) (*gitlaba.Variable, error)
+	createVariableMutex       sync.RWMutex
+	createVariableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *gitlaba.Variable
+	}
+	createVariableReturns struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	createVariableReturnsOnCall map[int]struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	DeleteVariableStub        func(context.Context, string, string, string) error
+	deleteVariableMutex       sync.RWMutex
+	deleteVariableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	deleteVariableReturns struct {
+		result1 error
+	}
+	deleteVariableReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetVariableStub        func(context.Context, string, string, string) (*gitlaba.Variable, error)
+	getVariableMutex       sync.RWMutex
+	getVariableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}
+	getVariableReturns struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	getVariableReturnsOnCall map[int]struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	UpdateVariableStub        func(context.Context, string, *gitlaba.Variable) (*gitlaba.Variable, error)
+	updateVariableMutex       sync.RWMutex
+	updateVariableArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *gitlaba.Variable
+	}
+	updateVariableReturns struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	updateVariableReturnsOnCall map[int]struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGitlabAPI) CreateVariable(arg1 context.Context, arg2 string, arg3 *gitlaba.Variable) (*gitlaba.Variable, error) {
+	fake.createVariableMutex.Lock()
+	ret, specificReturn := fake.createVariableReturnsOnCall[len(fake.createVariableArgsForCall)]
+	fake.createVariableArgsForCall = append(fake.createVariableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *gitlaba.Variable
+	}{arg1, arg2, arg3})
+	stub := fake.CreateVariableStub
+	fakeReturns := fake.createVariableReturns
+	fake.recordInvocation("CreateVariable", []interface{}{arg1, arg2, arg3})
+	fake.createVariableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGitlabAPI) CreateVariableCallCount() int {
+	fake.createVariableMutex.RLock()
+	defer fake.createVariableMutex.RUnlock()
+	return len(fake.createVariableArgsForCall)
+}
+
+func (fake *FakeGitlabAPI) CreateVariableCalls(stub func(context.Context, string, *gitlaba.Variable) (*gitlaba.Variable, error)) {
+	fake.createVariableMutex.Lock()
+	defer fake.createVariableMutex.Unlock()
+	fake.CreateVariableStub = stub
+}
+
+func (fake *FakeGitlabAPI) CreateVariableArgsForCall(i int) (context.Context, string, *gitlaba.Variable) {
+	fake.createVariableMutex.RLock()
+	defer fake.createVariableMutex.RUnlock()
+	argsForCall := fake.createVariableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGitlabAPI) CreateVariableReturns(result1 *gitlaba.Variable, result2 error) {
+	fake.createVariableMutex.Lock()
+	defer fake.createVariableMutex.Unlock()
+	fake.CreateVariableStub = nil
+	fake.createVariableReturns = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) CreateVariableReturnsOnCall(i int, result1 *gitlaba.Variable, result2 error) {
+	fake.createVariableMutex.Lock()
+	defer fake.createVariableMutex.Unlock()
+	fake.CreateVariableStub = nil
+	if fake.createVariableReturnsOnCall == nil {
+		fake.createVariableReturnsOnCall = make(map[int]struct {
+			result1 *gitlaba.Variable
+			result2 error
+		})
+	}
+	fake.createVariableReturnsOnCall[i] = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) DeleteVariable(arg1 context.Context, arg2 string, arg3 string, arg4 string) error {
+	fake.deleteVariableMutex.Lock()
+	ret, specificReturn := fake.deleteVariableReturnsOnCall[len(fake.deleteVariableArgsForCall)]
+	fake.deleteVariableArgsForCall = append(fake.deleteVariableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DeleteVariableStub
+	fakeReturns := fake.deleteVariableReturns
+	fake.recordInvocation("DeleteVariable", []interface{}{arg1, arg2, arg3, arg4})
+	fake.deleteVariableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeGitlabAPI) DeleteVariableCallCount() int {
+	fake.deleteVariableMutex.RLock()
+	defer fake.deleteVariableMutex.RUnlock()
+	return len(fake.deleteVariableArgsForCall)
+}
+
+func (fake *FakeGitlabAPI) DeleteVariableCalls(stub func(context.Context, string, string, string) error) {
+	fake.deleteVariableMutex.Lock()
+	defer fake.deleteVariableMutex.Unlock()
+	fake.DeleteVariableStub = stub
+}
+
+func (fake *FakeGitlabAPI) DeleteVariableArgsForCall(i int) (context.Context, string, string, string) {
+	fake.deleteVariableMutex.RLock()
+	defer fake.deleteVariableMutex.RUnlock()
+	argsForCall := fake.deleteVariableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitlabAPI) DeleteVariableReturns(result1 error) {
+	fake.deleteVariableMutex.Lock()
+	defer fake.deleteVariableMutex.Unlock()
+	fake.DeleteVariableStub = nil
+	fake.deleteVariableReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGitlabAPI) DeleteVariableReturnsOnCall(i int, result1 error) {
+	fake.deleteVariableMutex.Lock()
+	defer fake.deleteVariableMutex.Unlock()
+	fake.DeleteVariableStub = nil
+	if fake.deleteVariableReturnsOnCall == nil {
+		fake.deleteVariableReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteVariableReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeGitlabAPI) GetVariable(arg1 context.Context, arg2 string, arg3 string, arg4 string) (*gitlaba.Variable, error) {
+	fake.getVariableMutex.Lock()
+	ret, specificReturn := fake.getVariableReturnsOnCall[len(fake.getVariableArgsForCall)]
+	fake.getVariableArgsForCall = append(fake.getVariableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.GetVariableStub
+	fakeReturns := fake.getVariableReturns
+	fake.recordInvocation("GetVariable", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getVariableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGitlabAPI) GetVariableCallCount() int {
+	fake.getVariableMutex.RLock()
+	defer fake.getVariableMutex.RUnlock()
+	return len(fake.getVariableArgsForCall)
+}
+
+func (fake *FakeGitlabAPI) GetVariableCalls(stub func(context.Context, string, string, string) (*gitlaba.Variable, error)) {
+	fake.getVariableMutex.Lock()
+	defer fake.getVariableMutex.Unlock()
+	fake.GetVariableStub = stub
+}
+
+func (fake *FakeGitlabAPI) GetVariableArgsForCall(i int) (context.Context, string, string, string) {
+	fake.getVariableMutex.RLock()
+	defer fake.getVariableMutex.RUnlock()
+	argsForCall := fake.getVariableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeGitlabAPI) GetVariableReturns(result1 *gitlaba.Variable, result2 error) {
+	fake.getVariableMutex.Lock()
+	defer fake.getVariableMutex.Unlock()
+	fake.GetVariableStub = nil
+	fake.getVariableReturns = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) GetVariableReturnsOnCall(i int, result1 *gitlaba.Variable, result2 error) {
+	fake.getVariableMutex.Lock()
+	defer fake.getVariableMutex.Unlock()
+	fake.GetVariableStub = nil
+	if fake.getVariableReturnsOnCall == nil {
+		fake.getVariableReturnsOnCall = make(map[int]struct {
+			result1 *gitlaba.Variable
+			result2 error
+		})
+	}
+	fake.getVariableReturnsOnCall[i] = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) UpdateVariable(arg1 context.Context, arg2 string, arg3 *gitlaba.Variable) (*gitlaba.Variable, error) {
+	fake.updateVariableMutex.Lock()
+	ret, specificReturn := fake.updateVariableReturnsOnCall[len(fake.updateVariableArgsForCall)]
+	fake.updateVariableArgsForCall = append(fake.updateVariableArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *gitlaba.Variable
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateVariableStub
+	fakeReturns := fake.updateVariableReturns
+	fake.recordInvocation("UpdateVariable", []interface{}{arg1, arg2, arg3})
+	fake.updateVariableMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGitlabAPI) UpdateVariableCallCount() int {
+	fake.updateVariableMutex.RLock()
+	defer fake.updateVariableMutex.RUnlock()
+	return len(fake.updateVariableArgsForCall)
+}
+
+func (fake *FakeGitlabAPI) UpdateVariableCalls(stub func(context.Context, string, *gitlaba.Variable) (*gitlaba.Variable, error)) {
+	fake.updateVariableMutex.Lock()
+	defer fake.updateVariableMutex.Unlock()
+	fake.UpdateVariableStub = stub
+}
+
+func (fake *FakeGitlabAPI) UpdateVariableArgsForCall(i int) (context.Context, string, *gitlaba.Variable) {
+	fake.updateVariableMutex.RLock()
+	defer fake.updateVariableMutex.RUnlock()
+	argsForCall := fake.updateVariableArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeGitlabAPI) UpdateVariableReturns(result1 *gitlaba.Variable, result2 error) {
+	fake.updateVariableMutex.Lock()
+	defer fake.updateVariableMutex.Unlock()
+	fake.UpdateVariableStub = nil
+	fake.updateVariableReturns = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) UpdateVariableReturnsOnCall(i int, result1 *gitlaba.Variable, result2 error) {
+	fake.updateVariableMutex.Lock()
+	defer fake.updateVariableMutex.Unlock()
+	fake.UpdateVariableStub = nil
+	if fake.updateVariableReturnsOnCall == nil {
+		fake.updateVariableReturnsOnCall = make(map[int]struct {
+			result1 *gitlaba.Variable
+			result2 error
+		})
+	}
+	fake.updateVariableReturnsOnCall[i] = struct {
+		result1 *gitlaba.Variable
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitlabAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createVariableMutex.RLock()
+	defer fake.createVariableMutex.RUnlock()
+	fake.deleteVariableMutex.RLock()
+	defer fake.deleteVariableMutex.RUnlock()
+	fake.getVariableMutex.RLock()
+	defer fake.getVariableMutex.RUnlock()
+	fake.updateVariableMutex.RLock()
+	defer fake.updateVariableMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeGitlabAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gitlab.GitlabAPI = new(FakeGitlabAPI)
@@ -0,0 +1,274 @@
+// Package kubernetes implements sidecred.SecretStore on top of Kubernetes Secrets.
+package kubernetes
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/internal/kubernetes"
+)
+
+// Labels and annotations used to mark secrets that are managed by sidecred.
+const (
+	ManagedByLabel      = "app.kubernetes.io/managed-by"
+	ManagedByValue      = "sidecred"
+	NamespaceAnnotation = "sidecred.telia.io/namespace"
+)
+
+var (
+	// illegalNameCharactersRegex matches characters that are not allowed in the name of a Secret (RFC 1123 subdomain).
+	illegalNameCharactersRegex = regexp.MustCompile("[^a-z0-9.-]+")
+
+	// illegalKeyCharactersRegex matches characters that are not allowed in the keys of a Secret.
+	illegalKeyCharactersRegex = regexp.MustCompile("[^a-zA-Z0-9._-]+")
+)
+
+// New creates a new sidecred.SecretStore using Kubernetes Secrets.
+func New(client KubernetesAPI, options ...option) sidecred.SecretStore {
+	s := &store{
+		client:         client,
+		namespace:      "default",
+		secretTemplate: "{{ .Namespace }}-{{ .Name }}",
+	}
+	for _, optionFunc := range options {
+		optionFunc(s)
+	}
+	return s
+}
+
+type option func(*store)
+
+// WithSecretTemplate sets the secret name template when instantiating a new store.
+func WithSecretTemplate(t string) option {
+	return func(s *store) {
+		s.secretTemplate = t
+	}
+}
+
+// WithNamespace sets the default Kubernetes namespace for secrets.
+func WithNamespace(namespace string) option {
+	return func(s *store) {
+		s.namespace = namespace
+	}
+}
+
+type store struct {
+	client         KubernetesAPI
+	namespace      string
+	secretTemplate string
+}
+
+// config that can be passed to the Configure method of this store.
+//
+// By default, each credential is written to a separate Secret (named using the secret template) under the key
+// "value". When secret_name is set, all credentials are written as keys (named using the secret template) in the
+// same Secret instead, which can be mounted or used with envFrom as a whole.
+type config struct {
+	SecretTemplate string            `json:"secret_template"`
+	Namespace      string            `json:"namespace"`
+	SecretName     string            `json:"secret_name"`
+	Labels         map[string]string `json:"labels"`
+	Annotations    map[string]string `json:"annotations"`
+}
+
+// Type implements sidecred.SecretStore.
+func (s *store) Type() sidecred.StoreType {
+	return sidecred.Kubernetes
+}
+
+// Write implements sidecred.SecretStore.
+func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Credential, config json.RawMessage) (string, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("parse config: %s", err)
+	}
+	name, err := sidecred.BuildSecretTemplate(c.SecretTemplate, namespace, secret.Name)
+	if err != nil {
+		return "", fmt.Errorf("build secret name: %s", err)
+	}
+
+	key := "value"
+	if c.SecretName != "" {
+		name, key = c.SecretName, sanitizeKey(name)
+	} else {
+		name = sanitizeName(name)
+	}
+
+	existing, err := s.client.GetSecret(ctx, c.Namespace, name)
+	if err != nil {
+		return "", fmt.Errorf("get secret: %s", err)
+	}
+	if existing == nil {
+		_, err = s.client.CreateSecret(ctx, &kubernetes.Secret{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata: kubernetes.ObjectMeta{
+				Name:        name,
+				Namespace:   c.Namespace,
+				Labels:      s.labels(c),
+				Annotations: s.annotations(c, namespace),
+			},
+			Type: "Opaque",
+			Data: map[string][]byte{key: []byte(secret.Value)},
+		})
+		if err != nil {
+			return "", fmt.Errorf("create secret: %s", err)
+		}
+		return buildPath(c.Namespace, name, key), nil
+	}
+
+	if !isManaged(existing) {
+		return "", fmt.Errorf("secret %s/%s exists and is not managed by sidecred", c.Namespace, name)
+	}
+	// Patch only the fields managed by sidecred, so that other fields (e.g. owner references) are left untouched.
+	if _, err := s.client.PatchSecret(ctx, c.Namespace, name, &kubernetes.SecretPatch{
+		Metadata: kubernetes.PatchMeta{
+			Labels:          s.labels(c),
+			Annotations:     s.annotations(c, namespace),
+			ResourceVersion: existing.Metadata.ResourceVersion,
+		},
+		Data: map[string][]byte{key: []byte(secret.Value)},
+	}); err != nil {
+		return "", fmt.Errorf("update secret: %s", err)
+	}
+	return buildPath(c.Namespace, name, key), nil
+}
+
+// Read implements sidecred.SecretStore.
+func (s *store) Read(ctx context.Context, path string, _ json.RawMessage) (string, bool, error) {
+	namespace, name, key, err := parsePath(path)
+	if err != nil {
+		return "", false, err
+	}
+	secret, err := s.client.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return "", false, fmt.Errorf("get secret: %s", err)
+	}
+	if secret == nil {
+		return "", false, nil
+	}
+	value, found := secret.Data[key]
+	if !found {
+		return "", false, nil
+	}
+	return string(value), true, nil
+}
+
+// Delete implements sidecred.SecretStore.
+func (s *store) Delete(ctx context.Context, path string, _ json.RawMessage) error {
+	namespace, name, key, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	secret, err := s.client.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return fmt.Errorf("get secret: %s", err)
+	}
+	if secret == nil {
+		return nil
+	}
+	if !isManaged(secret) {
+		return fmt.Errorf("secret %s/%s is not managed by sidecred", namespace, name)
+	}
+	if _, found := secret.Data[key]; !found {
+		return nil
+	}
+
+	// Delete the secret when the last key is removed, otherwise remove the key (a nil value removes it in the patch).
+	if len(secret.Data) == 1 {
+		if err := s.client.DeleteSecret(ctx, namespace, name); err != nil && !kubernetes.IsNotFound(err) {
+			return fmt.Errorf("delete secret: %s", err)
+		}
+		return nil
+	}
+	if _, err := s.client.PatchSecret(ctx, namespace, name, &kubernetes.SecretPatch{
+		Metadata: kubernetes.PatchMeta{ResourceVersion: secret.Metadata.ResourceVersion},
+		Data:     map[string][]byte{key: nil},
+	}); err != nil {
+		return fmt.Errorf("update secret: %s", err)
+	}
+	return nil
+}
+
+// parseConfig parses and validates the config.
+func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
+	c := &config{}
+	if err := sidecred.UnmarshalConfig(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.SecretTemplate == "" {
+		c.SecretTemplate = s.secretTemplate
+	}
+	if c.Namespace == "" {
+		c.Namespace = s.namespace
+	}
+	if c.SecretName != "" && c.SecretName != sanitizeName(c.SecretName) {
+		return nil, fmt.Errorf("invalid secret name: %q", c.SecretName)
+	}
+	if _, found := c.Labels[ManagedByLabel]; found {
+		return nil, fmt.Errorf("label %q is reserved", ManagedByLabel)
+	}
+	return c, nil
+}
+
+func (s *store) labels(c *config) map[string]string {
+	labels := make(map[string]string, len(c.Labels)+1)
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	labels[ManagedByLabel] = ManagedByValue
+	return labels
+}
+
+func (s *store) annotations(c *config, namespace string) map[string]string {
+	annotations := make(map[string]string, len(c.Annotations)+1)
+	for k, v := range c.Annotations {
+		annotations[k] = v
+	}
+	annotations[NamespaceAnnotation] = namespace
+	return annotations
+}
+
+func isManaged(secret *kubernetes.Secret) bool {
+	return secret.Metadata.Labels[ManagedByLabel] == ManagedByValue
+}
+
+// buildPath returns the path (reference) to a key in a secret.
+func buildPath(namespace, name, key string) string {
+	return namespace + "/" + name + "/" + key
+}
+
+// parsePath parses paths returned by buildPath.
+func parsePath(path string) (namespace, name, key string, err error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf("invalid path: %q", path)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// sanitizeName returns a valid name for a Secret.
+func sanitizeName(name string) string {
+	name = illegalNameCharactersRegex.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-.")
+}
+
+// sanitizeKey returns a valid key for the data in a Secret.
+func sanitizeKey(key string) string {
+	return illegalKeyCharactersRegex.ReplaceAllString(key, "_")
+}
+
+// KubernetesAPI wraps the Kubernetes API for Secrets.
+//
+//counterfeiter:generate . KubernetesAPI
+type KubernetesAPI interface {
+	GetSecret(ctx context.Context, namespace, name string) (*kubernetes.Secret, error)
+	CreateSecret(ctx context.Context, secret *kubernetes.Secret) (*kubernetes.Secret, error)
+	PatchSecret(ctx context.Context, namespace, name string, patch *kubernetes.SecretPatch) (*kubernetes.Secret, error)
+	DeleteSecret(ctx context.Context, namespace, name string) error
+}
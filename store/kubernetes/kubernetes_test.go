@@ -0,0 +1,273 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/internal/kubernetes"
+	kubernetesstore "github.com/telia-oss/sidecred/store/kubernetes"
+	"github.com/telia-oss/sidecred/store/kubernetes/kubernetesfakes"
+)
+
+// newFakeKubernetesAPI returns a fake which stores secrets in memory.
+func newFakeKubernetesAPI(secrets map[string]*kubernetes.Secret) *kubernetesfakes.FakeKubernetesAPI {
+	fake := &kubernetesfakes.FakeKubernetesAPI{}
+	fake.GetSecretStub = func(_ context.Context, namespace, name string) (*kubernetes.Secret, error) {
+		return secrets[namespace+"/"+name], nil
+	}
+	fake.CreateSecretStub = func(_ context.Context, secret *kubernetes.Secret) (*kubernetes.Secret, error) {
+		secrets[secret.Metadata.Namespace+"/"+secret.Metadata.Name] = secret
+		return secret, nil
+	}
+	fake.PatchSecretStub = func(_ context.Context, namespace, name string, patch *kubernetes.SecretPatch) (*kubernetes.Secret, error) {
+		secret := secrets[namespace+"/"+name]
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for k, v := range patch.Data {
+			if v == nil {
+				delete(secret.Data, k)
+				continue
+			}
+			secret.Data[k] = v
+		}
+		if secret.Metadata.Labels == nil {
+			secret.Metadata.Labels = make(map[string]string)
+		}
+		for k, v := range patch.Metadata.Labels {
+			secret.Metadata.Labels[k] = v
+		}
+		if secret.Metadata.Annotations == nil {
+			secret.Metadata.Annotations = make(map[string]string)
+		}
+		for k, v := range patch.Metadata.Annotations {
+			secret.Metadata.Annotations[k] = v
+		}
+		return secret, nil
+	}
+	fake.DeleteSecretStub = func(_ context.Context, namespace, name string) error {
+		delete(secrets, namespace+"/"+name)
+		return nil
+	}
+	return fake
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		description         string
+		config              json.RawMessage
+		existing            map[string]*kubernetes.Secret
+		expectedPath        string
+		expectedSecret      string
+		expectedData        map[string][]byte
+		expectedLabels      map[string]string
+		expectedCreateCalls int
+		expectedPatchCalls  int
+		expectedPatch       *kubernetes.SecretPatch
+		expectedError       string
+	}{
+		{
+			description:         "kubernetes store works",
+			expectedPath:        "default/team-name-secret-name/value",
+			expectedSecret:      "default/team-name-secret-name",
+			expectedData:        map[string][]byte{"value": []byte("secret-value")},
+			expectedLabels:      map[string]string{"app.kubernetes.io/managed-by": "sidecred"},
+			expectedCreateCalls: 1,
+		},
+		{
+			description:         "supports config",
+			config:              []byte(`{"namespace":"team","secret_template":"sidecred.{{ .Name }}","labels":{"team":"team-name"}}`),
+			expectedPath:        "team/sidecred.secret-name/value",
+			expectedSecret:      "team/sidecred.secret-name",
+			expectedData:        map[string][]byte{"value": []byte("secret-value")},
+			expectedLabels:      map[string]string{"app.kubernetes.io/managed-by": "sidecred", "team": "team-name"},
+			expectedCreateCalls: 1,
+		},
+		{
+			description: "writes keys to shared secrets",
+			config:      []byte(`{"secret_name":"shared","secret_template":"{{ .Name }}"}`),
+			existing: map[string]*kubernetes.Secret{
+				"default/shared": {
+					Metadata: kubernetes.ObjectMeta{
+						Name:      "shared",
+						Namespace: "default",
+						Labels:    map[string]string{"app.kubernetes.io/managed-by": "sidecred"},
+					},
+					Data: map[string][]byte{"other": []byte("other-value")},
+				},
+			},
+			expectedPath:       "default/shared/Secret_Name",
+			expectedSecret:     "default/shared",
+			expectedData:       map[string][]byte{"other": []byte("other-value"), "Secret_Name": []byte("secret-value")},
+			expectedLabels:     map[string]string{"app.kubernetes.io/managed-by": "sidecred"},
+			expectedPatchCalls: 1,
+		},
+		{
+			description: "only patches the fields managed by sidecred",
+			config:      []byte(`{"secret_name":"shared","secret_template":"{{ .Name }}"}`),
+			existing: map[string]*kubernetes.Secret{
+				"default/shared": {
+					Metadata: kubernetes.ObjectMeta{
+						Name:            "shared",
+						Namespace:       "default",
+						ResourceVersion: "5",
+						Labels:          map[string]string{"app.kubernetes.io/managed-by": "sidecred", "other": "label"},
+					},
+					Data: map[string][]byte{"other": []byte("other-value")},
+				},
+			},
+			expectedPath:       "default/shared/Secret_Name",
+			expectedSecret:     "default/shared",
+			expectedData:       map[string][]byte{"other": []byte("other-value"), "Secret_Name": []byte("secret-value")},
+			expectedLabels:     map[string]string{"app.kubernetes.io/managed-by": "sidecred", "other": "label"},
+			expectedPatchCalls: 1,
+			expectedPatch: &kubernetes.SecretPatch{
+				Metadata: kubernetes.PatchMeta{
+					Labels:          map[string]string{"app.kubernetes.io/managed-by": "sidecred"},
+					Annotations:     map[string]string{"sidecred.telia.io/namespace": "team-name"},
+					ResourceVersion: "5",
+				},
+				Data: map[string][]byte{"Secret_Name": []byte("secret-value")},
+			},
+		},
+		{
+			description: "does not overwrite secrets that are not managed by sidecred",
+			existing: map[string]*kubernetes.Secret{
+				"default/team-name-secret-name": {
+					Metadata: kubernetes.ObjectMeta{Name: "team-name-secret-name", Namespace: "default"},
+				},
+			},
+			expectedError: "secret default/team-name-secret-name exists and is not managed by sidecred",
+		},
+		{
+			description:   "reserved labels cannot be overridden",
+			config:        []byte(`{"labels":{"app.kubernetes.io/managed-by":"someone"}}`),
+			expectedError: `parse config: label "app.kubernetes.io/managed-by" is reserved`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			secrets := tc.existing
+			if secrets == nil {
+				secrets = make(map[string]*kubernetes.Secret)
+			}
+			fakeKubernetesAPI := newFakeKubernetesAPI(secrets)
+
+			s := kubernetesstore.New(fakeKubernetesAPI)
+			path, err := s.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "Secret_Name", Value: "secret-value"}, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+			assert.Equal(t, tc.expectedCreateCalls, fakeKubernetesAPI.CreateSecretCallCount(), "create calls")
+			assert.Equal(t, tc.expectedPatchCalls, fakeKubernetesAPI.PatchSecretCallCount(), "patch calls")
+			if tc.expectedPatch != nil {
+				_, _, _, patch := fakeKubernetesAPI.PatchSecretArgsForCall(0)
+				assert.Equal(t, tc.expectedPatch, patch)
+			}
+
+			secret, found := secrets[tc.expectedSecret]
+			require.True(t, found)
+			assert.Equal(t, tc.expectedData, secret.Data)
+			assert.Equal(t, tc.expectedLabels, secret.Metadata.Labels)
+			assert.Equal(t, "team-name", secret.Metadata.Annotations["sidecred.telia.io/namespace"])
+
+			value, found, err := s.Read(context.TODO(), path, tc.config)
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "secret-value", value)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	managed := map[string]string{"app.kubernetes.io/managed-by": "sidecred"}
+
+	tests := []struct {
+		description         string
+		path                string
+		existing            map[string]*kubernetes.Secret
+		expectedSecrets     []string
+		expectedPatchCalls  int
+		expectedDeleteCalls int
+		expectedError       string
+	}{
+		{
+			description: "deletes the secret when the last key is removed",
+			path:        "default/secret-name/value",
+			existing: map[string]*kubernetes.Secret{
+				"default/secret-name": {
+					Metadata: kubernetes.ObjectMeta{Name: "secret-name", Namespace: "default", Labels: managed},
+					Data:     map[string][]byte{"value": []byte("secret-value")},
+				},
+			},
+			expectedDeleteCalls: 1,
+		},
+		{
+			description: "removes keys from shared secrets",
+			path:        "default/shared/secret-name",
+			existing: map[string]*kubernetes.Secret{
+				"default/shared": {
+					Metadata: kubernetes.ObjectMeta{Name: "shared", Namespace: "default", Labels: managed},
+					Data:     map[string][]byte{"secret-name": []byte("secret-value"), "other": []byte("other-value")},
+				},
+			},
+			expectedSecrets:    []string{"default/shared"},
+			expectedPatchCalls: 1,
+		},
+		{
+			description: "does nothing if the secret does not exist",
+			path:        "default/secret-name/value",
+		},
+		{
+			description: "does not delete secrets that are not managed by sidecred",
+			path:        "default/secret-name/value",
+			existing: map[string]*kubernetes.Secret{
+				"default/secret-name": {
+					Metadata: kubernetes.ObjectMeta{Name: "secret-name", Namespace: "default"},
+					Data:     map[string][]byte{"value": []byte("secret-value")},
+				},
+			},
+			expectedSecrets: []string{"default/secret-name"},
+			expectedError:   "secret default/secret-name is not managed by sidecred",
+		},
+		{
+			description:   "fails for invalid paths",
+			path:          "secret-name",
+			expectedError: `invalid path: "secret-name"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			secrets := tc.existing
+			if secrets == nil {
+				secrets = make(map[string]*kubernetes.Secret)
+			}
+			fakeKubernetesAPI := newFakeKubernetesAPI(secrets)
+
+			err := kubernetesstore.New(fakeKubernetesAPI).Delete(context.TODO(), tc.path, nil)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedPatchCalls, fakeKubernetesAPI.PatchSecretCallCount(), "patch calls")
+			assert.Equal(t, tc.expectedDeleteCalls, fakeKubernetesAPI.DeleteSecretCallCount(), "delete calls")
+
+			var actual []string
+			for k := range secrets {
+				actual = append(actual, k)
+			}
+			assert.ElementsMatch(t, tc.expectedSecrets, actual)
+		})
+	}
+}
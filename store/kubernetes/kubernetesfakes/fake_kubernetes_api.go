@@ -0,0 +1,366 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package kubernetesfakes
+
+import (
+	"context"
+	"sync"
+
+	kubernetesa "github.com/telia-oss/sidecred/internal/kubernetes"
+	"github.com/telia-oss/sidecred/store/kubernetes"
+)
+
+type FakeKubernetesAPI struct {
+	CreateSecretStub        func(context.Context, *kubernetesa.Secret) (*kubernetesa.Secret, error)
+	createSecretMutex       sync.RWMutex
+	createSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 *kubernetesa.Secret
+	}
+	createSecretReturns struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	createSecretReturnsOnCall map[int]struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	DeleteSecretStub        func(context.Context, string, string) error
+	deleteSecretMutex       sync.RWMutex
+	deleteSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	deleteSecretReturns struct {
+		result1 error
+	}
+	deleteSecretReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetSecretStub        func(context.Context, string, string) (*kubernetesa.Secret, error)
+	getSecretMutex       sync.RWMutex
+	getSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	getSecretReturns struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	getSecretReturnsOnCall map[int]struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	PatchSecretStub        func(context.Context, string, string, *kubernetesa.SecretPatch) (*kubernetesa.Secret, error)
+	patchSecretMutex       sync.RWMutex
+	patchSecretArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *kubernetesa.SecretPatch
+	}
+	patchSecretReturns struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	patchSecretReturnsOnCall map[int]struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKubernetesAPI) CreateSecret(arg1 context.Context, arg2 *kubernetesa.Secret) (*kubernetesa.Secret, error) {
+	fake.createSecretMutex.Lock()
+	ret, specificReturn := fake.createSecretReturnsOnCall[len(fake.createSecretArgsForCall)]
+	fake.createSecretArgsForCall = append(fake.createSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 *kubernetesa.Secret
+	}{arg1, arg2})
+	stub := fake.CreateSecretStub
+	fakeReturns := fake.createSecretReturns
+	fake.recordInvocation("CreateSecret", []interface{}{arg1, arg2})
+	fake.createSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKubernetesAPI) CreateSecretCallCount() int {
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	return len(fake.createSecretArgsForCall)
+}
+
+func (fake *FakeKubernetesAPI) CreateSecretCalls(stub func(context.Context, *kubernetesa.Secret) (*kubernetesa.Secret, error)) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = stub
+}
+
+func (fake *FakeKubernetesAPI) CreateSecretArgsForCall(i int) (context.Context, *kubernetesa.Secret) {
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	argsForCall := fake.createSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKubernetesAPI) CreateSecretReturns(result1 *kubernetesa.Secret, result2 error) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = nil
+	fake.createSecretReturns = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) CreateSecretReturnsOnCall(i int, result1 *kubernetesa.Secret, result2 error) {
+	fake.createSecretMutex.Lock()
+	defer fake.createSecretMutex.Unlock()
+	fake.CreateSecretStub = nil
+	if fake.createSecretReturnsOnCall == nil {
+		fake.createSecretReturnsOnCall = make(map[int]struct {
+			result1 *kubernetesa.Secret
+			result2 error
+		})
+	}
+	fake.createSecretReturnsOnCall[i] = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecret(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.deleteSecretMutex.Lock()
+	ret, specificReturn := fake.deleteSecretReturnsOnCall[len(fake.deleteSecretArgsForCall)]
+	fake.deleteSecretArgsForCall = append(fake.deleteSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteSecretStub
+	fakeReturns := fake.deleteSecretReturns
+	fake.recordInvocation("DeleteSecret", []interface{}{arg1, arg2, arg3})
+	fake.deleteSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecretCallCount() int {
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	return len(fake.deleteSecretArgsForCall)
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecretCalls(stub func(context.Context, string, string) error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = stub
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecretArgsForCall(i int) (context.Context, string, string) {
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	argsForCall := fake.deleteSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecretReturns(result1 error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = nil
+	fake.deleteSecretReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKubernetesAPI) DeleteSecretReturnsOnCall(i int, result1 error) {
+	fake.deleteSecretMutex.Lock()
+	defer fake.deleteSecretMutex.Unlock()
+	fake.DeleteSecretStub = nil
+	if fake.deleteSecretReturnsOnCall == nil {
+		fake.deleteSecretReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteSecretReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKubernetesAPI) GetSecret(arg1 context.Context, arg2 string, arg3 string) (*kubernetesa.Secret, error) {
+	fake.getSecretMutex.Lock()
+	ret, specificReturn := fake.getSecretReturnsOnCall[len(fake.getSecretArgsForCall)]
+	fake.getSecretArgsForCall = append(fake.getSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.GetSecretStub
+	fakeReturns := fake.getSecretReturns
+	fake.recordInvocation("GetSecret", []interface{}{arg1, arg2, arg3})
+	fake.getSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKubernetesAPI) GetSecretCallCount() int {
+	fake.getSecretMutex.RLock()
+	defer fake.getSecretMutex.RUnlock()
+	return len(fake.getSecretArgsForCall)
+}
+
+func (fake *FakeKubernetesAPI) GetSecretCalls(stub func(context.Context, string, string) (*kubernetesa.Secret, error)) {
+	fake.getSecretMutex.Lock()
+	defer fake.getSecretMutex.Unlock()
+	fake.GetSecretStub = stub
+}
+
+func (fake *FakeKubernetesAPI) GetSecretArgsForCall(i int) (context.Context, string, string) {
+	fake.getSecretMutex.RLock()
+	defer fake.getSecretMutex.RUnlock()
+	argsForCall := fake.getSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKubernetesAPI) GetSecretReturns(result1 *kubernetesa.Secret, result2 error) {
+	fake.getSecretMutex.Lock()
+	defer fake.getSecretMutex.Unlock()
+	fake.GetSecretStub = nil
+	fake.getSecretReturns = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) GetSecretReturnsOnCall(i int, result1 *kubernetesa.Secret, result2 error) {
+	fake.getSecretMutex.Lock()
+	defer fake.getSecretMutex.Unlock()
+	fake.GetSecretStub = nil
+	if fake.getSecretReturnsOnCall == nil {
+		fake.getSecretReturnsOnCall = make(map[int]struct {
+			result1 *kubernetesa.Secret
+			result2 error
+		})
+	}
+	fake.getSecretReturnsOnCall[i] = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) PatchSecret(arg1 context.Context, arg2 string, arg3 string, arg4 *kubernetesa.SecretPatch) (*kubernetesa.Secret, error) {
+	fake.patchSecretMutex.Lock()
+	ret, specificReturn := fake.patchSecretReturnsOnCall[len(fake.patchSecretArgsForCall)]
+	fake.patchSecretArgsForCall = append(fake.patchSecretArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 *kubernetesa.SecretPatch
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PatchSecretStub
+	fakeReturns := fake.patchSecretReturns
+	fake.recordInvocation("PatchSecret", []interface{}{arg1, arg2, arg3, arg4})
+	fake.patchSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKubernetesAPI) PatchSecretCallCount() int {
+	fake.patchSecretMutex.RLock()
+	defer fake.patchSecretMutex.RUnlock()
+	return len(fake.patchSecretArgsForCall)
+}
+
+func (fake *FakeKubernetesAPI) PatchSecretCalls(stub func(context.Context, string, string, *kubernetesa.SecretPatch) (*kubernetesa.Secret, error)) {
+	fake.patchSecretMutex.Lock()
+	defer fake.patchSecretMutex.Unlock()
+	fake.PatchSecretStub = stub
+}
+
+func (fake *FakeKubernetesAPI) PatchSecretArgsForCall(i int) (context.Context, string, string, *kubernetesa.SecretPatch) {
+	fake.patchSecretMutex.RLock()
+	defer fake.patchSecretMutex.RUnlock()
+	argsForCall := fake.patchSecretArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeKubernetesAPI) PatchSecretReturns(result1 *kubernetesa.Secret, result2 error) {
+	fake.patchSecretMutex.Lock()
+	defer fake.patchSecretMutex.Unlock()
+	fake.PatchSecretStub = nil
+	fake.patchSecretReturns = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) PatchSecretReturnsOnCall(i int, result1 *kubernetesa.Secret, result2 error) {
+	fake.patchSecretMutex.Lock()
+	defer fake.patchSecretMutex.Unlock()
+	fake.PatchSecretStub = nil
+	if fake.patchSecretReturnsOnCall == nil {
+		fake.patchSecretReturnsOnCall = make(map[int]struct {
+			result1 *kubernetesa.Secret
+			result2 error
+		})
+	}
+	fake.patchSecretReturnsOnCall[i] = struct {
+		result1 *kubernetesa.Secret
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKubernetesAPI) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createSecretMutex.RLock()
+	defer fake.createSecretMutex.RUnlock()
+	fake.deleteSecretMutex.RLock()
+	defer fake.deleteSecretMutex.RUnlock()
+	fake.getSecretMutex.RLock()
+	defer fake.getSecretMutex.RUnlock()
+	fake.patchSecretMutex.RLock()
+	defer fake.patchSecretMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKubernetesAPI) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kubernetes.KubernetesAPI = new(FakeKubernetesAPI)
@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -48,8 +50,15 @@ type store struct {
 }
 
 // config that can be passed to the Configure method of this store.
+//
+// Secrets are force deleted (without recovery) unless recovery_window_days is set (7-30 days). When bundle is
+// enabled, all credentials from a request are written as a single JSON secret (named using the request name).
 type config struct {
-	SecretTemplate string `json:"secret_template"`
+	SecretTemplate     string            `json:"secret_template"`
+	KMSKeyID           string            `json:"kms_key_id"`
+	Tags               map[string]string `json:"tags"`
+	RecoveryWindowDays int64             `json:"recovery_window_days"`
+	Bundle             bool              `json:"bundle"`
 }
 
 // Type implements sidecred.SecretStore.
@@ -67,33 +76,97 @@ func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Cr
 	if err != nil {
 		return "", fmt.Errorf("build secret path: %s", err)
 	}
+	if err := s.write(path, secret.Description, secret.Value, c); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Bundle implements sidecred.SecretBundler.
+func (s *store) Bundle(config json.RawMessage) bool {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return false // Write will surface the error.
+	}
+	return c.Bundle
+}
+
+// WriteBundle implements sidecred.SecretBundler.
+func (s *store) WriteBundle(ctx context.Context, namespace, name string, secrets []*sidecred.Credential, config json.RawMessage) (string, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("parse config: %s", err)
+	}
+	path, err := sidecred.BuildSecretTemplate(c.SecretTemplate, namespace, name)
+	if err != nil {
+		return "", fmt.Errorf("build secret path: %s", err)
+	}
+	values := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		values[secret.Name] = secret.Value
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("marshal bundle: %s", err)
+	}
+	if err := s.write(path, secrets[0].Description, string(b), c); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (s *store) write(path, description, value string, c *config) error {
+	var tags []*secretsmanager.Tag
+	for k, v := range c.Tags {
+		tags = append(tags, &secretsmanager.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	sort.Slice(tags, func(i, j int) bool { return *tags[i].Key < *tags[j].Key })
+	var kmsKeyID *string
+	if c.KMSKeyID != "" {
+		kmsKeyID = aws.String(c.KMSKeyID)
+	}
 
 	// Creating and handling the error results in fewer API calls than
 	// checking if it exists before creating the secret and then updating it.
-	_, err = s.client.CreateSecret(&secretsmanager.CreateSecretInput{
+	_, err := s.client.CreateSecret(&secretsmanager.CreateSecretInput{
 		Name:        aws.String(path),
-		Description: aws.String(secret.Description),
+		Description: aws.String(description),
+		KmsKeyId:    kmsKeyID,
+		Tags:        tags,
 	})
 	if err != nil {
 		var e awserr.Error
 		if !errors.As(err, &e) {
-			return "", fmt.Errorf("convert aws error: %s", err)
+			return fmt.Errorf("convert aws error: %s", err)
+		}
+		switch e.Code() {
+		case secretsmanager.ErrCodeResourceExistsException:
+		case secretsmanager.ErrCodeInvalidRequestException:
+			// Secrets that are scheduled for deletion (when using a recovery window) can be restored.
+			if !strings.Contains(e.Message(), "scheduled for deletion") {
+				return err
+			}
+			if _, restoreErr := s.client.RestoreSecret(&secretsmanager.RestoreSecretInput{SecretId: aws.String(path)}); restoreErr != nil {
+				return err
+			}
+		default:
+			return err
 		}
-		if e.Code() != secretsmanager.ErrCodeResourceExistsException {
-			return "", err
+		// Tags are only set when creating a secret, so they are kept in sync for existing secrets.
+		if len(tags) > 0 {
+			if _, err := s.client.TagResource(&secretsmanager.TagResourceInput{SecretId: aws.String(path), Tags: tags}); err != nil {
+				return fmt.Errorf("tag secret: %s", err)
+			}
 		}
 	}
 
 	_, err = s.client.UpdateSecret(&secretsmanager.UpdateSecretInput{
 		SecretId:     aws.String(path),
-		Description:  aws.String(secret.Description),
-		SecretString: aws.String(secret.Value),
+		Description:  aws.String(description),
+		KmsKeyId:     kmsKeyID,
+		SecretString: aws.String(value),
 	})
-	if err != nil {
-		return "", err
-	}
-
-	return path, nil
+	return err
 }
 
 // Read implements sidecred.SecretStore.
@@ -116,11 +189,20 @@ func (s *store) Read(ctx context.Context, path string, _ json.RawMessage) (strin
 }
 
 // Delete implements sidecred.SecretStore.
-func (s *store) Delete(ctx context.Context, path string, _ json.RawMessage) error {
-	_, err := s.client.DeleteSecret(&secretsmanager.DeleteSecretInput{
+func (s *store) Delete(ctx context.Context, path string, config json.RawMessage) error {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse config: %s", err)
+	}
+	input := &secretsmanager.DeleteSecretInput{
 		SecretId:                   aws.String(path),
 		ForceDeleteWithoutRecovery: aws.Bool(true),
-	})
+	}
+	if c.RecoveryWindowDays > 0 {
+		input.ForceDeleteWithoutRecovery = nil
+		input.RecoveryWindowInDays = aws.Int64(c.RecoveryWindowDays)
+	}
+	_, err = s.client.DeleteSecret(input)
 	if err != nil {
 		var e awserr.Error
 		if !errors.As(err, &e) {
@@ -143,14 +225,20 @@ func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
 	if c.SecretTemplate == "" {
 		c.SecretTemplate = s.secretTemplate
 	}
+	if c.RecoveryWindowDays != 0 && (c.RecoveryWindowDays < 7 || c.RecoveryWindowDays > 30) {
+		return nil, fmt.Errorf("%q must be between 7 and 30 days", "recovery_window_days")
+	}
 	return c, nil
 }
 
 // SecretsManagerAPI wraps the interface for the API and provides a mocked implementation.
+//
 //counterfeiter:generate . SecretsManagerAPI
 type SecretsManagerAPI interface {
 	CreateSecret(input *secretsmanager.CreateSecretInput) (*secretsmanager.CreateSecretOutput, error)
 	UpdateSecret(input *secretsmanager.UpdateSecretInput) (*secretsmanager.UpdateSecretOutput, error)
 	GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
 	DeleteSecret(input *secretsmanager.DeleteSecretInput) (*secretsmanager.DeleteSecretOutput, error)
+	RestoreSecret(input *secretsmanager.RestoreSecretInput) (*secretsmanager.RestoreSecretOutput, error)
+	TagResource(input *secretsmanager.TagResourceInput) (*secretsmanager.TagResourceOutput, error)
 }
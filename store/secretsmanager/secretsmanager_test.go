@@ -160,13 +160,28 @@ func TestDelete(t *testing.T) {
 
 	tests := []struct {
 		description       string
+		config            json.RawMessage
 		secretPath        string
 		deleteSecretError error
+		expectedInput     *secretsmanager.DeleteSecretInput
 		expectedError     error
 	}{
 		{
 			description: "works as expected",
 			secretPath:  secretPath,
+			expectedInput: &secretsmanager.DeleteSecretInput{
+				SecretId:                   aws.String(secretPath),
+				ForceDeleteWithoutRecovery: aws.Bool(true),
+			},
+		},
+		{
+			description: "supports recovery window",
+			config:      []byte(`{"recovery_window_days":7}`),
+			secretPath:  secretPath,
+			expectedInput: &secretsmanager.DeleteSecretInput{
+				SecretId:             aws.String(secretPath),
+				RecoveryWindowInDays: aws.Int64(7),
+			},
 		},
 		{
 			description:       "ignores error if secret does not exist",
@@ -188,10 +203,132 @@ func TestDelete(t *testing.T) {
 			client.DeleteSecretReturns(nil, tc.deleteSecretError)
 
 			store := secretstore.New(client)
-			err := store.Delete(context.TODO(), tc.secretPath, nil)
+			err := store.Delete(context.TODO(), tc.secretPath, tc.config)
 
 			assert.Equal(t, tc.expectedError, err)
 			assert.Equal(t, 1, client.DeleteSecretCallCount())
+			if tc.expectedInput != nil {
+				assert.Equal(t, tc.expectedInput, client.DeleteSecretArgsForCall(0))
+			}
+		})
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	var (
+		secret          = &sidecred.Credential{Name: "secret-name", Value: "secret-value", Description: "description"}
+		deletionMessage = "You can't create this secret because a secret with this name is already scheduled for deletion."
+		secretPath      = "/team-name/secret-name"
+		tags            = []*secretsmanager.Tag{
+			{Key: aws.String("owner"), Value: aws.String("team-name")},
+			{Key: aws.String("team"), Value: aws.String("team-name")},
+		}
+	)
+
+	tests := []struct {
+		description         string
+		config              json.RawMessage
+		createError         error
+		restoreError        error
+		expectedCreateInput *secretsmanager.CreateSecretInput
+		expectedUpdateInput *secretsmanager.UpdateSecretInput
+		expectedTagCalls    int
+		expectedRestoreCall int
+		expectedError       string
+	}{
+		{
+			description: "supports kms key and tags",
+			config:      []byte(`{"kms_key_id":"alias/key","tags":{"team":"team-name","owner":"team-name"}}`),
+			expectedCreateInput: &secretsmanager.CreateSecretInput{
+				Name:        aws.String(secretPath),
+				Description: aws.String("description"),
+				KmsKeyId:    aws.String("alias/key"),
+				Tags:        tags,
+			},
+			expectedUpdateInput: &secretsmanager.UpdateSecretInput{
+				SecretId:     aws.String(secretPath),
+				Description:  aws.String("description"),
+				KmsKeyId:     aws.String("alias/key"),
+				SecretString: aws.String("secret-value"),
+			},
+		},
+		{
+			description:      "tags existing secrets",
+			config:           []byte(`{"tags":{"team":"team-name","owner":"team-name"}}`),
+			createError:      awserr.New(secretsmanager.ErrCodeResourceExistsException, "", nil),
+			expectedTagCalls: 1,
+		},
+		{
+			description:         "restores secrets that are scheduled for deletion",
+			createError:         awserr.New(secretsmanager.ErrCodeInvalidRequestException, deletionMessage, nil),
+			expectedRestoreCall: 1,
+		},
+		{
+			description:         "returns the create error if the secret cannot be restored",
+			createError:         awserr.New(secretsmanager.ErrCodeInvalidRequestException, deletionMessage, nil),
+			restoreError:        awserr.New("failure", "", nil),
+			expectedRestoreCall: 1,
+			expectedError:       "InvalidRequestException: " + deletionMessage,
+		},
+		{
+			description:   "does not restore secrets for other invalid requests",
+			createError:   awserr.New(secretsmanager.ErrCodeInvalidRequestException, "invalid", nil),
+			expectedError: "InvalidRequestException: invalid",
+		},
+		{
+			description:   "validates the recovery window",
+			config:        []byte(`{"recovery_window_days":1}`),
+			expectedError: `parse config: "recovery_window_days" must be between 7 and 30 days`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			client := &secretsmanagerfakes.FakeSecretsManagerAPI{}
+			client.CreateSecretReturns(nil, tc.createError)
+			client.RestoreSecretReturns(nil, tc.restoreError)
+
+			path, err := secretstore.New(client).Write(context.TODO(), "team-name", secret, tc.config)
+			assert.Equal(t, tc.expectedRestoreCall, client.RestoreSecretCallCount(), "restore calls")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, secretPath, path)
+			assert.Equal(t, tc.expectedTagCalls, client.TagResourceCallCount(), "tag calls")
+			if tc.expectedTagCalls > 0 {
+				assert.Equal(t, tags, client.TagResourceArgsForCall(0).Tags)
+			}
+			if tc.expectedCreateInput != nil {
+				assert.Equal(t, tc.expectedCreateInput, client.CreateSecretArgsForCall(0))
+			}
+			if tc.expectedUpdateInput != nil {
+				assert.Equal(t, tc.expectedUpdateInput, client.UpdateSecretArgsForCall(0))
+			}
 		})
 	}
 }
+
+func TestWriteBundle(t *testing.T) {
+	client := &secretsmanagerfakes.FakeSecretsManagerAPI{}
+	store := secretstore.New(client)
+
+	bundler, ok := store.(sidecred.SecretBundler)
+	if !assert.True(t, ok, "implements sidecred.SecretBundler") {
+		return
+	}
+	assert.False(t, bundler.Bundle(nil))
+	assert.True(t, bundler.Bundle([]byte(`{"bundle":true}`)))
+
+	path, err := bundler.WriteBundle(context.TODO(), "team-name", "request-name", []*sidecred.Credential{
+		{Name: "request-name-access-key", Value: "access-key", Description: "AWS credentials managed by sidecred."},
+		{Name: "request-name-secret-key", Value: "secret-key"},
+	}, []byte(`{"bundle":true}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "/team-name/request-name", path)
+
+	input := client.UpdateSecretArgsForCall(0)
+	assert.Equal(t, "AWS credentials managed by sidecred.", aws.StringValue(input.Description))
+	assert.JSONEq(t, `{"request-name-access-key":"access-key","request-name-secret-key":"secret-key"}`, aws.StringValue(input.SecretString))
+}
@@ -48,6 +48,32 @@ type FakeSecretsManagerAPI struct {
 		result1 *secretsmanagera.GetSecretValueOutput
 		result2 error
 	}
+	RestoreSecretStub        func(*secretsmanagera.RestoreSecretInput) (*secretsmanagera.RestoreSecretOutput, error)
+	restoreSecretMutex       sync.RWMutex
+	restoreSecretArgsForCall []struct {
+		arg1 *secretsmanagera.RestoreSecretInput
+	}
+	restoreSecretReturns struct {
+		result1 *secretsmanagera.RestoreSecretOutput
+		result2 error
+	}
+	restoreSecretReturnsOnCall map[int]struct {
+		result1 *secretsmanagera.RestoreSecretOutput
+		result2 error
+	}
+	TagResourceStub        func(*secretsmanagera.TagResourceInput) (*secretsmanagera.TagResourceOutput, error)
+	tagResourceMutex       sync.RWMutex
+	tagResourceArgsForCall []struct {
+		arg1 *secretsmanagera.TagResourceInput
+	}
+	tagResourceReturns struct {
+		result1 *secretsmanagera.TagResourceOutput
+		result2 error
+	}
+	tagResourceReturnsOnCall map[int]struct {
+		result1 *secretsmanagera.TagResourceOutput
+		result2 error
+	}
 	UpdateSecretStub        func(*secretsmanagera.UpdateSecretInput) (*secretsmanagera.UpdateSecretOutput, error)
 	updateSecretMutex       sync.RWMutex
 	updateSecretArgsForCall []struct {
@@ -257,6 +283,134 @@ func (fake *FakeSecretsManagerAPI) GetSecretValueReturnsOnCall(i int, result1 *s
 	}{result1, result2}
 }
 
+func (fake *FakeSecretsManagerAPI) RestoreSecret(arg1 *secretsmanagera.RestoreSecretInput) (*secretsmanagera.RestoreSecretOutput, error) {
+	fake.restoreSecretMutex.Lock()
+	ret, specificReturn := fake.restoreSecretReturnsOnCall[len(fake.restoreSecretArgsForCall)]
+	fake.restoreSecretArgsForCall = append(fake.restoreSecretArgsForCall, struct {
+		arg1 *secretsmanagera.RestoreSecretInput
+	}{arg1})
+	stub := fake.RestoreSecretStub
+	fakeReturns := fake.restoreSecretReturns
+	fake.recordInvocation("RestoreSecret", []interface{}{arg1})
+	fake.restoreSecretMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSecretsManagerAPI) RestoreSecretCallCount() int {
+	fake.restoreSecretMutex.RLock()
+	defer fake.restoreSecretMutex.RUnlock()
+	return len(fake.restoreSecretArgsForCall)
+}
+
+func (fake *FakeSecretsManagerAPI) RestoreSecretCalls(stub func(*secretsmanagera.RestoreSecretInput) (*secretsmanagera.RestoreSecretOutput, error)) {
+	fake.restoreSecretMutex.Lock()
+	defer fake.restoreSecretMutex.Unlock()
+	fake.RestoreSecretStub = stub
+}
+
+func (fake *FakeSecretsManagerAPI) RestoreSecretArgsForCall(i int) *secretsmanagera.RestoreSecretInput {
+	fake.restoreSecretMutex.RLock()
+	defer fake.restoreSecretMutex.RUnlock()
+	argsForCall := fake.restoreSecretArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSecretsManagerAPI) RestoreSecretReturns(result1 *secretsmanagera.RestoreSecretOutput, result2 error) {
+	fake.restoreSecretMutex.Lock()
+	defer fake.restoreSecretMutex.Unlock()
+	fake.RestoreSecretStub = nil
+	fake.restoreSecretReturns = struct {
+		result1 *secretsmanagera.RestoreSecretOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSecretsManagerAPI) RestoreSecretReturnsOnCall(i int, result1 *secretsmanagera.RestoreSecretOutput, result2 error) {
+	fake.restoreSecretMutex.Lock()
+	defer fake.restoreSecretMutex.Unlock()
+	fake.RestoreSecretStub = nil
+	if fake.restoreSecretReturnsOnCall == nil {
+		fake.restoreSecretReturnsOnCall = make(map[int]struct {
+			result1 *secretsmanagera.RestoreSecretOutput
+			result2 error
+		})
+	}
+	fake.restoreSecretReturnsOnCall[i] = struct {
+		result1 *secretsmanagera.RestoreSecretOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSecretsManagerAPI) TagResource(arg1 *secretsmanagera.TagResourceInput) (*secretsmanagera.TagResourceOutput, error) {
+	fake.tagResourceMutex.Lock()
+	ret, specificReturn := fake.tagResourceReturnsOnCall[len(fake.tagResourceArgsForCall)]
+	fake.tagResourceArgsForCall = append(fake.tagResourceArgsForCall, struct {
+		arg1 *secretsmanagera.TagResourceInput
+	}{arg1})
+	stub := fake.TagResourceStub
+	fakeReturns := fake.tagResourceReturns
+	fake.recordInvocation("TagResource", []interface{}{arg1})
+	fake.tagResourceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSecretsManagerAPI) TagResourceCallCount() int {
+	fake.tagResourceMutex.RLock()
+	defer fake.tagResourceMutex.RUnlock()
+	return len(fake.tagResourceArgsForCall)
+}
+
+func (fake *FakeSecretsManagerAPI) TagResourceCalls(stub func(*secretsmanagera.TagResourceInput) (*secretsmanagera.TagResourceOutput, error)) {
+	fake.tagResourceMutex.Lock()
+	defer fake.tagResourceMutex.Unlock()
+	fake.TagResourceStub = stub
+}
+
+func (fake *FakeSecretsManagerAPI) TagResourceArgsForCall(i int) *secretsmanagera.TagResourceInput {
+	fake.tagResourceMutex.RLock()
+	defer fake.tagResourceMutex.RUnlock()
+	argsForCall := fake.tagResourceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSecretsManagerAPI) TagResourceReturns(result1 *secretsmanagera.TagResourceOutput, result2 error) {
+	fake.tagResourceMutex.Lock()
+	defer fake.tagResourceMutex.Unlock()
+	fake.TagResourceStub = nil
+	fake.tagResourceReturns = struct {
+		result1 *secretsmanagera.TagResourceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSecretsManagerAPI) TagResourceReturnsOnCall(i int, result1 *secretsmanagera.TagResourceOutput, result2 error) {
+	fake.tagResourceMutex.Lock()
+	defer fake.tagResourceMutex.Unlock()
+	fake.TagResourceStub = nil
+	if fake.tagResourceReturnsOnCall == nil {
+		fake.tagResourceReturnsOnCall = make(map[int]struct {
+			result1 *secretsmanagera.TagResourceOutput
+			result2 error
+		})
+	}
+	fake.tagResourceReturnsOnCall[i] = struct {
+		result1 *secretsmanagera.TagResourceOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSecretsManagerAPI) UpdateSecret(arg1 *secretsmanagera.UpdateSecretInput) (*secretsmanagera.UpdateSecretOutput, error) {
 	fake.updateSecretMutex.Lock()
 	ret, specificReturn := fake.updateSecretReturnsOnCall[len(fake.updateSecretArgsForCall)]
@@ -330,6 +484,10 @@ func (fake *FakeSecretsManagerAPI) Invocations() map[string][][]interface{} {
 	defer fake.deleteSecretMutex.RUnlock()
 	fake.getSecretValueMutex.RLock()
 	defer fake.getSecretValueMutex.RUnlock()
+	fake.restoreSecretMutex.RLock()
+	defer fake.restoreSecretMutex.RUnlock()
+	fake.tagResourceMutex.RLock()
+	defer fake.tagResourceMutex.RUnlock()
 	fake.updateSecretMutex.RLock()
 	defer fake.updateSecretMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
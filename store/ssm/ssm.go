@@ -7,6 +7,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -57,8 +60,37 @@ type store struct {
 }
 
 // config that can be passed to the Configure method of this store.
+//
+// The kms_key_id overrides the default KMS key of the store. Parameter policies require the Advanced tier,
+// which is used by default when policies are configured. The expiration policy (if enabled) is set to the
+// expiration of the credential, and the expiration notification is sent relative to it (which means that
+// the expiration notification requires the expiration policy).
 type config struct {
-	SecretTemplate string `json:"secret_template"`
+	SecretTemplate string            `json:"secret_template"`
+	KMSKeyID       string            `json:"kms_key_id"`
+	Tier           string            `json:"tier"`
+	Tags           map[string]string `json:"tags"`
+	Policies       *policies         `json:"policies"`
+}
+
+// policies that can be attached to a parameter.
+type policies struct {
+	Expiration             bool                    `json:"expiration"`
+	ExpirationNotification *expirationNotification `json:"expiration_notification"`
+}
+
+// expirationNotification is sent a number of days or hours before the credential expires.
+type expirationNotification struct {
+	Before int    `json:"before"`
+	Unit   string `json:"unit"`
+}
+
+// parameterPolicy as defined in the SSM documentation:
+// https://docs.aws.amazon.com/systems-manager/latest/userguide/parameter-store-policies.html
+type parameterPolicy struct {
+	Type       string            `json:"Type"`
+	Version    string            `json:"Version"`
+	Attributes map[string]string `json:"Attributes"`
 }
 
 // Type implements sidecred.SecretStore.
@@ -85,16 +117,74 @@ func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Cr
 		Overwrite:   aws.Bool(true),
 	}
 
-	if s.kmsKeyID != "" {
-		input.SetKeyId(s.kmsKeyID)
+	if c.KMSKeyID != "" {
+		input.SetKeyId(c.KMSKeyID)
+	}
+	if c.Tier != "" {
+		input.SetTier(c.Tier)
+	}
+	if c.Policies != nil {
+		policies, err := buildPolicies(c.Policies, secret.Expiration)
+		if err != nil {
+			return "", fmt.Errorf("build policies: %s", err)
+		}
+		if policies != "" {
+			input.SetPolicies(policies)
+		}
 	}
 
 	if _, err = s.client.PutParameter(input); err != nil {
 		return "", err
 	}
+
+	// Tags cannot be set when overwriting a parameter, so they are added separately.
+	if len(c.Tags) > 0 {
+		var tags []*ssm.Tag
+		for k, v := range c.Tags {
+			tags = append(tags, &ssm.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		sort.Slice(tags, func(i, j int) bool { return *tags[i].Key < *tags[j].Key })
+
+		if _, err := s.client.AddTagsToResource(&ssm.AddTagsToResourceInput{
+			ResourceId:   aws.String(path),
+			ResourceType: aws.String(ssm.ResourceTypeForTaggingParameter),
+			Tags:         tags,
+		}); err != nil {
+			return "", fmt.Errorf("tag parameter: %s", err)
+		}
+	}
 	return path, nil
 }
 
+// buildPolicies returns the JSON encoded parameter policies for a credential with the given expiration.
+func buildPolicies(p *policies, expiration time.Time) (string, error) {
+	var out []*parameterPolicy
+	if !expiration.IsZero() {
+		if p.Expiration {
+			out = append(out, &parameterPolicy{
+				Type:       "Expiration",
+				Version:    "1.0",
+				Attributes: map[string]string{"Timestamp": expiration.UTC().Format(time.RFC3339)},
+			})
+		}
+		if n := p.ExpirationNotification; n != nil {
+			out = append(out, &parameterPolicy{
+				Type:       "ExpirationNotification",
+				Version:    "1.0",
+				Attributes: map[string]string{"Before": strconv.Itoa(n.Before), "Unit": n.Unit},
+			})
+		}
+	}
+	if len(out) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 // Read implements sidecred.SecretStore.
 func (s *store) Read(ctx context.Context, path string, _ json.RawMessage) (string, bool, error) {
 	out, err := s.client.GetParameter(&ssm.GetParameterInput{
@@ -142,6 +232,36 @@ func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
 	if c.SecretTemplate == "" {
 		c.SecretTemplate = s.secretTemplate
 	}
+	if c.KMSKeyID == "" {
+		c.KMSKeyID = s.kmsKeyID
+	}
+	switch c.Tier {
+	case "", ssm.ParameterTierStandard, ssm.ParameterTierAdvanced, ssm.ParameterTierIntelligentTiering:
+	default:
+		return nil, fmt.Errorf("invalid tier: %q", c.Tier)
+	}
+	if p := c.Policies; p != nil {
+		if n := p.ExpirationNotification; n != nil {
+			// Notifications are only sent for parameters that have an expiration policy.
+			if !p.Expiration {
+				return nil, fmt.Errorf("%q requires %q to be enabled", "expiration_notification", "expiration")
+			}
+			if n.Before < 1 {
+				return nil, fmt.Errorf("%q must be greater than 0", "expiration_notification.before")
+			}
+			switch n.Unit {
+			case "Days", "Hours":
+			default:
+				return nil, fmt.Errorf("invalid expiration notification unit: %q", n.Unit)
+			}
+		}
+		switch c.Tier {
+		case "":
+			c.Tier = ssm.ParameterTierAdvanced
+		case ssm.ParameterTierStandard:
+			return nil, fmt.Errorf("policies are not supported for the %q tier", c.Tier)
+		}
+	}
 	return c, nil
 }
 
@@ -151,4 +271,5 @@ type SSMAPI interface {
 	PutParameter(input *ssm.PutParameterInput) (*ssm.PutParameterOutput, error)
 	GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
 	DeleteParameter(input *ssm.DeleteParameterInput) (*ssm.DeleteParameterOutput, error)
+	AddTagsToResource(input *ssm.AddTagsToResourceInput) (*ssm.AddTagsToResourceOutput, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -168,3 +169,117 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteConfig(t *testing.T) {
+	var (
+		expiration = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		secret     = &sidecred.Credential{Name: "secret-name", Value: "secret-value", Expiration: expiration}
+		secretPath = "/team-name/secret-name"
+	)
+
+	tests := []struct {
+		description   string
+		config        json.RawMessage
+		expectedInput *ssm.PutParameterInput
+		expectedTags  []*ssm.Tag
+		expectedError string
+	}{
+		{
+			description: "uses the default kms key",
+			expectedInput: &ssm.PutParameterInput{
+				Name:        aws.String(secretPath),
+				Description: aws.String(""),
+				Value:       aws.String("secret-value"),
+				Type:        aws.String("SecureString"),
+				Overwrite:   aws.Bool(true),
+				KeyId:       aws.String("default-key"),
+			},
+		},
+		{
+			description: "supports kms key and tier",
+			config:      []byte(`{"kms_key_id":"alias/key","tier":"Advanced"}`),
+			expectedInput: &ssm.PutParameterInput{
+				Name:        aws.String(secretPath),
+				Description: aws.String(""),
+				Value:       aws.String("secret-value"),
+				Type:        aws.String("SecureString"),
+				Overwrite:   aws.Bool(true),
+				KeyId:       aws.String("alias/key"),
+				Tier:        aws.String("Advanced"),
+			},
+		},
+		{
+			description: "supports policies",
+			config:      []byte(`{"policies":{"expiration":true,"expiration_notification":{"before":12,"unit":"Hours"}}}`),
+			expectedInput: &ssm.PutParameterInput{
+				Name:        aws.String(secretPath),
+				Description: aws.String(""),
+				Value:       aws.String("secret-value"),
+				Type:        aws.String("SecureString"),
+				Overwrite:   aws.Bool(true),
+				KeyId:       aws.String("default-key"),
+				Tier:        aws.String("Advanced"),
+				Policies: aws.String(`[` +
+					`{"Type":"Expiration","Version":"1.0","Attributes":{"Timestamp":"2020-01-02T03:04:05Z"}},` +
+					`{"Type":"ExpirationNotification","Version":"1.0","Attributes":{"Before":"12","Unit":"Hours"}}` +
+					`]`),
+			},
+		},
+		{
+			description: "supports tags",
+			config:      []byte(`{"tags":{"team":"team-name","owner":"team-name"}}`),
+			expectedTags: []*ssm.Tag{
+				{Key: aws.String("owner"), Value: aws.String("team-name")},
+				{Key: aws.String("team"), Value: aws.String("team-name")},
+			},
+		},
+		{
+			description:   "validates the tier",
+			config:        []byte(`{"tier":"Premium"}`),
+			expectedError: `parse config: invalid tier: "Premium"`,
+		},
+		{
+			description:   "policies are not supported for standard parameters",
+			config:        []byte(`{"tier":"Standard","policies":{"expiration":true}}`),
+			expectedError: `parse config: policies are not supported for the "Standard" tier`,
+		},
+		{
+			description:   "validates the expiration notification",
+			config:        []byte(`{"policies":{"expiration":true,"expiration_notification":{"before":1,"unit":"Weeks"}}}`),
+			expectedError: `parse config: invalid expiration notification unit: "Weeks"`,
+		},
+		{
+			description:   "expiration notification requires the expiration policy",
+			config:        []byte(`{"policies":{"expiration_notification":{"before":1,"unit":"Days"}}}`),
+			expectedError: `parse config: "expiration_notification" requires "expiration" to be enabled`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			client := &ssmfakes.FakeSSMAPI{}
+
+			store := secretstore.New(client, secretstore.WithKMSKeyID("default-key"))
+			path, err := store.Write(context.TODO(), "team-name", secret, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, secretPath, path)
+			if tc.expectedInput != nil {
+				assert.Equal(t, tc.expectedInput, client.PutParameterArgsForCall(0))
+			}
+			if tc.expectedTags == nil {
+				assert.Equal(t, 0, client.AddTagsToResourceCallCount())
+				return
+			}
+			if assert.Equal(t, 1, client.AddTagsToResourceCallCount()) {
+				input := client.AddTagsToResourceArgsForCall(0)
+				assert.Equal(t, secretPath, aws.StringValue(input.ResourceId))
+				assert.Equal(t, "Parameter", aws.StringValue(input.ResourceType))
+				assert.Equal(t, tc.expectedTags, input.Tags)
+			}
+		})
+	}
+}
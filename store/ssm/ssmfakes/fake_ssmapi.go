@@ -9,6 +9,19 @@ import (
 )
 
 type FakeSSMAPI struct {
+	AddTagsToResourceStub        func(*ssma.AddTagsToResourceInput) (*ssma.AddTagsToResourceOutput, error)
+	addTagsToResourceMutex       sync.RWMutex
+	addTagsToResourceArgsForCall []struct {
+		arg1 *ssma.AddTagsToResourceInput
+	}
+	addTagsToResourceReturns struct {
+		result1 *ssma.AddTagsToResourceOutput
+		result2 error
+	}
+	addTagsToResourceReturnsOnCall map[int]struct {
+		result1 *ssma.AddTagsToResourceOutput
+		result2 error
+	}
 	DeleteParameterStub        func(*ssma.DeleteParameterInput) (*ssma.DeleteParameterOutput, error)
 	deleteParameterMutex       sync.RWMutex
 	deleteParameterArgsForCall []struct {
@@ -52,6 +65,70 @@ type FakeSSMAPI struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeSSMAPI) AddTagsToResource(arg1 *ssma.AddTagsToResourceInput) (*ssma.AddTagsToResourceOutput, error) {
+	fake.addTagsToResourceMutex.Lock()
+	ret, specificReturn := fake.addTagsToResourceReturnsOnCall[len(fake.addTagsToResourceArgsForCall)]
+	fake.addTagsToResourceArgsForCall = append(fake.addTagsToResourceArgsForCall, struct {
+		arg1 *ssma.AddTagsToResourceInput
+	}{arg1})
+	stub := fake.AddTagsToResourceStub
+	fakeReturns := fake.addTagsToResourceReturns
+	fake.recordInvocation("AddTagsToResource", []interface{}{arg1})
+	fake.addTagsToResourceMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSSMAPI) AddTagsToResourceCallCount() int {
+	fake.addTagsToResourceMutex.RLock()
+	defer fake.addTagsToResourceMutex.RUnlock()
+	return len(fake.addTagsToResourceArgsForCall)
+}
+
+func (fake *FakeSSMAPI) AddTagsToResourceCalls(stub func(*ssma.AddTagsToResourceInput) (*ssma.AddTagsToResourceOutput, error)) {
+	fake.addTagsToResourceMutex.Lock()
+	defer fake.addTagsToResourceMutex.Unlock()
+	fake.AddTagsToResourceStub = stub
+}
+
+func (fake *FakeSSMAPI) AddTagsToResourceArgsForCall(i int) *ssma.AddTagsToResourceInput {
+	fake.addTagsToResourceMutex.RLock()
+	defer fake.addTagsToResourceMutex.RUnlock()
+	argsForCall := fake.addTagsToResourceArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSSMAPI) AddTagsToResourceReturns(result1 *ssma.AddTagsToResourceOutput, result2 error) {
+	fake.addTagsToResourceMutex.Lock()
+	defer fake.addTagsToResourceMutex.Unlock()
+	fake.AddTagsToResourceStub = nil
+	fake.addTagsToResourceReturns = struct {
+		result1 *ssma.AddTagsToResourceOutput
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSSMAPI) AddTagsToResourceReturnsOnCall(i int, result1 *ssma.AddTagsToResourceOutput, result2 error) {
+	fake.addTagsToResourceMutex.Lock()
+	defer fake.addTagsToResourceMutex.Unlock()
+	fake.AddTagsToResourceStub = nil
+	if fake.addTagsToResourceReturnsOnCall == nil {
+		fake.addTagsToResourceReturnsOnCall = make(map[int]struct {
+			result1 *ssma.AddTagsToResourceOutput
+			result2 error
+		})
+	}
+	fake.addTagsToResourceReturnsOnCall[i] = struct {
+		result1 *ssma.AddTagsToResourceOutput
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSSMAPI) DeleteParameter(arg1 *ssma.DeleteParameterInput) (*ssma.DeleteParameterOutput, error) {
 	fake.deleteParameterMutex.Lock()
 	ret, specificReturn := fake.deleteParameterReturnsOnCall[len(fake.deleteParameterArgsForCall)]
@@ -247,6 +324,8 @@ func (fake *FakeSSMAPI) PutParameterReturnsOnCall(i int, result1 *ssma.PutParame
 func (fake *FakeSSMAPI) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.addTagsToResourceMutex.RLock()
+	defer fake.addTagsToResourceMutex.RUnlock()
 	fake.deleteParameterMutex.RLock()
 	defer fake.deleteParameterMutex.RUnlock()
 	fake.getParameterMutex.RLock()
@@ -0,0 +1,298 @@
+// Package vault implements sidecred.SecretStore on top of the HashiCorp Vault KV secrets engine (version 2).
+package vault
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/telia-oss/sidecred"
+	vaultapi "github.com/telia-oss/sidecred/internal/vault"
+)
+
+// New creates a new sidecred.SecretStore using the Vault KV secrets engine (version 2).
+func New(client VaultAPI, options ...option) sidecred.SecretStore {
+	s := &store{
+		client:         client,
+		mount:          "secret",
+		secretTemplate: "{{ .Namespace }}/{{ .Name }}",
+	}
+	for _, optionFunc := range options {
+		optionFunc(s)
+	}
+	return s
+}
+
+type option func(*store)
+
+// WithMount sets the default mount path of the KV secrets engine.
+func WithMount(mount string) option {
+	return func(s *store) {
+		s.mount = mount
+	}
+}
+
+// WithSecretTemplate sets the path template when instantiating a new store.
+func WithSecretTemplate(t string) option {
+	return func(s *store) {
+		s.secretTemplate = t
+	}
+}
+
+// WithHardDelete permanently destroys secret versions by default, instead of (soft) deleting them.
+func WithHardDelete(hardDelete bool) option {
+	return func(s *store) {
+		s.hardDelete = hardDelete
+	}
+}
+
+type store struct {
+	client         VaultAPI
+	mount          string
+	secretTemplate string
+	hardDelete     bool
+}
+
+// config that can be passed to the Configure method of this store.
+//
+// By default, each credential is written to a separate secret (named using the secret template) under the field
+// "value". When secret_name is set, all credentials are written as fields (named using the secret template) of the
+// same secret instead. Each write creates a new version of the secret, and the version is recorded in the path
+// stored in the state. Delete either deletes (soft) or destroys (hard) the version, depending on hard_delete.
+type config struct {
+	Mount          string `json:"mount"`
+	SecretTemplate string `json:"secret_template"`
+	SecretName     string `json:"secret_name"`
+	HardDelete     *bool  `json:"hard_delete"`
+}
+
+// Type implements sidecred.SecretStore.
+func (s *store) Type() sidecred.StoreType {
+	return sidecred.VaultSecrets
+}
+
+// Write implements sidecred.SecretStore.
+func (s *store) Write(ctx context.Context, namespace string, secret *sidecred.Credential, config json.RawMessage) (string, error) {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("parse config: %s", err)
+	}
+	name, err := sidecred.BuildSecretTemplate(c.SecretTemplate, namespace, secret.Name)
+	if err != nil {
+		return "", fmt.Errorf("build secret path: %s", err)
+	}
+
+	var (
+		field = "value"
+		data  = make(map[string]interface{})
+		cas   *int
+	)
+	if c.SecretName != "" {
+		name, field = c.SecretName, name
+
+		// Merge with the existing fields, and use check-and-set to avoid overwriting concurrent updates.
+		current, version, err := s.read(ctx, c.Mount, name, 0)
+		if err != nil {
+			return "", fmt.Errorf("read secret: %s", err)
+		}
+		for k, v := range current {
+			data[k] = v
+		}
+		cas = &version
+	}
+	name = strings.Trim(name, "/")
+	data[field] = secret.Value
+
+	version, err := s.write(ctx, c.Mount, name, data, cas)
+	if err != nil {
+		return "", fmt.Errorf("write secret: %s", err)
+	}
+	return buildPath(c.Mount, name, version, field), nil
+}
+
+// Read implements sidecred.SecretStore.
+func (s *store) Read(ctx context.Context, path string, _ json.RawMessage) (string, bool, error) {
+	mount, name, version, field, err := parsePath(path)
+	if err != nil {
+		return "", false, err
+	}
+	data, _, err := s.read(ctx, mount, name, version)
+	if err != nil {
+		return "", false, fmt.Errorf("read secret: %s", err)
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return "", false, nil
+	}
+	return value, true, nil
+}
+
+// Delete implements sidecred.SecretStore.
+func (s *store) Delete(ctx context.Context, path string, config json.RawMessage) error {
+	c, err := s.parseConfig(config)
+	if err != nil {
+		return fmt.Errorf("parse config: %s", err)
+	}
+	mount, name, version, field, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	versions := []int{version}
+
+	// The fields of a secret are carried over to later versions when writing to a shared secret, so the field is
+	// removed from the current version unless it has been written again since (i.e. the credential was rotated).
+	deleted, _, err := s.read(ctx, mount, name, version)
+	if err != nil {
+		return fmt.Errorf("read secret: %s", err)
+	}
+	if value, found := deleted[field]; found {
+		current, currentVersion, err := s.read(ctx, mount, name, 0)
+		if err != nil {
+			return fmt.Errorf("read secret: %s", err)
+		}
+		if v, found := current[field]; found && v == value {
+			delete(current, field)
+			if len(current) == 0 {
+				if currentVersion != version {
+					versions = append(versions, currentVersion)
+				}
+			} else if _, err := s.write(ctx, mount, name, current, &currentVersion); err != nil {
+				return fmt.Errorf("write secret: %s", err)
+			}
+		}
+	}
+
+	// Deleting or destroying versions that do not exist (or are already deleted) is not an error.
+	operation := "delete"
+	if *c.HardDelete {
+		operation = "destroy"
+	}
+	if _, err := s.client.Write(ctx, mount+"/"+operation+"/"+name, map[string]interface{}{"versions": versions}); err != nil {
+		return fmt.Errorf("%s secret version: %s", operation, err)
+	}
+	return nil
+}
+
+// parseConfig parses and validates the config.
+func (s *store) parseConfig(raw json.RawMessage) (*config, error) {
+	c := &config{}
+	if err := sidecred.UnmarshalConfig(raw, &c); err != nil {
+		return nil, err
+	}
+	if c.Mount == "" {
+		c.Mount = s.mount
+	}
+	c.Mount = strings.Trim(c.Mount, "/")
+	if strings.Contains("/"+c.Mount+"/", "/data/") {
+		return nil, fmt.Errorf("invalid mount: %q", c.Mount)
+	}
+	if c.SecretTemplate == "" {
+		c.SecretTemplate = s.secretTemplate
+	}
+	if c.HardDelete == nil {
+		c.HardDelete = &s.hardDelete
+	}
+	return c, nil
+}
+
+// read returns the data and version of a secret. The current version is returned if the version is 0,
+// and no data is returned if the version does not exist or has been deleted.
+func (s *store) read(ctx context.Context, mount, name string, version int) (map[string]interface{}, int, error) {
+	path := mount + "/data/" + name
+	if version > 0 {
+		path += "?version=" + strconv.Itoa(version)
+	}
+	secret, err := s.client.Read(ctx, path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if secret == nil {
+		return nil, 0, nil
+	}
+	var out struct {
+		Data     map[string]interface{} `json:"data"`
+		Metadata struct {
+			Version int `json:"version"`
+		} `json:"metadata"`
+	}
+	if err := convert(secret.Data, &out); err != nil {
+		return nil, 0, err
+	}
+	return out.Data, out.Metadata.Version, nil
+}
+
+// write creates a new version of a secret and returns the version.
+func (s *store) write(ctx context.Context, mount, name string, data map[string]interface{}, cas *int) (int, error) {
+	body := map[string]interface{}{"data": data}
+	if cas != nil {
+		body["options"] = map[string]interface{}{"cas": *cas}
+	}
+	secret, err := s.client.Write(ctx, mount+"/data/"+name, body)
+	if err != nil {
+		return 0, err
+	}
+	if secret == nil {
+		return 0, fmt.Errorf("empty response")
+	}
+	var out struct {
+		Version int `json:"version"`
+	}
+	if err := convert(secret.Data, &out); err != nil {
+		return 0, err
+	}
+	return out.Version, nil
+}
+
+// convert the generic data returned by the client to a structure.
+func convert(data map[string]interface{}, v interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal data: %s", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("unmarshal data: %s", err)
+	}
+	return nil
+}
+
+// buildPath returns the path (reference) to a field in a version of a secret, e.g. "secret/data/team/name?version=2#value".
+func buildPath(mount, name string, version int, field string) string {
+	return fmt.Sprintf("%s/data/%s?version=%d#%s", mount, name, version, field)
+}
+
+// parsePath parses paths returned by buildPath.
+func parsePath(path string) (mount, name string, version int, field string, err error) {
+	invalid := fmt.Errorf("invalid path: %q", path)
+
+	i := strings.LastIndex(path, "#")
+	if i < 0 || i == len(path)-1 {
+		return "", "", 0, "", invalid
+	}
+	path, field = path[:i], path[i+1:]
+
+	i = strings.LastIndex(path, "?version=")
+	if i < 0 {
+		return "", "", 0, "", invalid
+	}
+	if version, err = strconv.Atoi(path[i+len("?version="):]); err != nil || version < 1 {
+		return "", "", 0, "", invalid
+	}
+	path = path[:i]
+
+	parts := strings.SplitN(path, "/data/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, "", invalid
+	}
+	return parts[0], parts[1], version, field, nil
+}
+
+// VaultAPI wraps the Vault API.
+//
+//counterfeiter:generate . VaultAPI
+type VaultAPI interface {
+	Read(ctx context.Context, path string) (*vaultapi.Secret, error)
+	Write(ctx context.Context, path string, data map[string]interface{}) (*vaultapi.Secret, error)
+}
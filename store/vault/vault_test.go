@@ -0,0 +1,337 @@
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/telia-oss/sidecred"
+	"github.com/telia-oss/sidecred/config"
+	"github.com/telia-oss/sidecred/eventctx"
+	vaultapi "github.com/telia-oss/sidecred/internal/vault"
+	"github.com/telia-oss/sidecred/provider/random"
+	"github.com/telia-oss/sidecred/store/vault"
+	"github.com/telia-oss/sidecred/store/vault/vaultfakes"
+)
+
+type version struct {
+	data      map[string]interface{}
+	deleted   bool
+	destroyed bool
+}
+
+// newFakeVaultAPI returns a fake which implements a KV (version 2) secrets engine in memory.
+func newFakeVaultAPI(secrets map[string][]*version) *vaultfakes.FakeVaultAPI {
+	fake := &vaultfakes.FakeVaultAPI{}
+	fake.ReadStub = func(_ context.Context, path string) (*vaultapi.Secret, error) {
+		v := 0
+		if i := strings.Index(path, "?version="); i > 0 {
+			v, _ = strconv.Atoi(path[i+len("?version="):])
+			path = path[:i]
+		}
+		versions := secrets[path]
+		if v == 0 {
+			v = len(versions)
+		}
+		if v == 0 || v > len(versions) || versions[v-1].deleted || versions[v-1].destroyed {
+			return nil, nil
+		}
+		data := make(map[string]interface{})
+		for k, value := range versions[v-1].data {
+			data[k] = value
+		}
+		return &vaultapi.Secret{Data: map[string]interface{}{
+			"data":     data,
+			"metadata": map[string]interface{}{"version": float64(v)},
+		}}, nil
+	}
+	fake.WriteStub = func(_ context.Context, path string, data map[string]interface{}) (*vaultapi.Secret, error) {
+		switch {
+		case strings.Contains(path, "/data/"):
+			if options, ok := data["options"].(map[string]interface{}); ok {
+				if cas := options["cas"].(int); cas != len(secrets[path]) {
+					return nil, fmt.Errorf("check-and-set parameter did not match the current version")
+				}
+			}
+			secrets[path] = append(secrets[path], &version{data: data["data"].(map[string]interface{})})
+			return &vaultapi.Secret{Data: map[string]interface{}{"version": float64(len(secrets[path]))}}, nil
+		case strings.Contains(path, "/delete/"), strings.Contains(path, "/destroy/"):
+			key := strings.Replace(strings.Replace(path, "/delete/", "/data/", 1), "/destroy/", "/data/", 1)
+			for _, v := range data["versions"].([]int) {
+				if v > len(secrets[key]) {
+					continue
+				}
+				if strings.Contains(path, "/delete/") {
+					secrets[key][v-1].deleted = true
+				} else {
+					secrets[key][v-1].destroyed = true
+				}
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unexpected path: %s", path)
+	}
+	return fake
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		description    string
+		config         json.RawMessage
+		existing       map[string][]*version
+		expectedPath   string
+		expectedSecret string
+		expectedData   map[string]interface{}
+		expectedError  string
+	}{
+		{
+			description:    "vault store works",
+			expectedPath:   "secret/data/team-name/secret-name?version=1#value",
+			expectedSecret: "secret/data/team-name/secret-name",
+			expectedData:   map[string]interface{}{"value": "secret-value"},
+		},
+		{
+			description:    "supports config",
+			config:         []byte(`{"mount":"/kv/team/","secret_template":"sidecred/{{ .Name }}"}`),
+			expectedPath:   "kv/team/data/sidecred/secret-name?version=1#value",
+			expectedSecret: "kv/team/data/sidecred/secret-name",
+			expectedData:   map[string]interface{}{"value": "secret-value"},
+		},
+		{
+			description: "records the version in the path",
+			existing: map[string][]*version{
+				"secret/data/team-name/secret-name": {
+					{data: map[string]interface{}{"value": "old-value"}},
+				},
+			},
+			expectedPath:   "secret/data/team-name/secret-name?version=2#value",
+			expectedSecret: "secret/data/team-name/secret-name",
+			expectedData:   map[string]interface{}{"value": "secret-value"},
+		},
+		{
+			description: "writes fields to shared secrets",
+			config:      []byte(`{"secret_name":"team/shared","secret_template":"{{ .Name }}"}`),
+			existing: map[string][]*version{
+				"secret/data/team/shared": {
+					{data: map[string]interface{}{"other": "other-value"}},
+				},
+			},
+			expectedPath:   "secret/data/team/shared?version=2#secret-name",
+			expectedSecret: "secret/data/team/shared",
+			expectedData:   map[string]interface{}{"other": "other-value", "secret-name": "secret-value"},
+		},
+		{
+			description:   "fails for invalid mounts",
+			config:        []byte(`{"mount":"kv/data/team"}`),
+			expectedError: `parse config: invalid mount: "kv/data/team"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			secrets := tc.existing
+			if secrets == nil {
+				secrets = make(map[string][]*version)
+			}
+			s := vault.New(newFakeVaultAPI(secrets))
+
+			path, err := s.Write(context.TODO(), "team-name", &sidecred.Credential{Name: "secret-name", Value: "secret-value"}, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPath, path)
+
+			versions := secrets[tc.expectedSecret]
+			require.NotEmpty(t, versions)
+			assert.Equal(t, tc.expectedData, versions[len(versions)-1].data)
+
+			value, found, err := s.Read(context.TODO(), path, tc.config)
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "secret-value", value)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		description      string
+		config           json.RawMessage
+		path             string
+		existing         map[string][]*version
+		expectedVersions []*version
+		expectedError    string
+	}{
+		{
+			description: "soft deletes the version",
+			path:        "secret/data/team/secret-name?version=1#value",
+			existing: map[string][]*version{
+				"secret/data/team/secret-name": {
+					{data: map[string]interface{}{"value": "old-value"}},
+					{data: map[string]interface{}{"value": "new-value"}},
+				},
+			},
+			expectedVersions: []*version{
+				{data: map[string]interface{}{"value": "old-value"}, deleted: true},
+				{data: map[string]interface{}{"value": "new-value"}},
+			},
+		},
+		{
+			description: "hard deletes the version",
+			config:      []byte(`{"hard_delete":true}`),
+			path:        "secret/data/team/secret-name?version=1#value",
+			existing: map[string][]*version{
+				"secret/data/team/secret-name": {
+					{data: map[string]interface{}{"value": "old-value"}},
+					{data: map[string]interface{}{"value": "new-value"}},
+				},
+			},
+			expectedVersions: []*version{
+				{data: map[string]interface{}{"value": "old-value"}, destroyed: true},
+				{data: map[string]interface{}{"value": "new-value"}},
+			},
+		},
+		{
+			description: "deletes the current version when the last field is removed",
+			path:        "secret/data/team/secret-name?version=2#value",
+			existing: map[string][]*version{
+				"secret/data/team/secret-name": {
+					{data: map[string]interface{}{"value": "old-value"}, deleted: true},
+					{data: map[string]interface{}{"value": "new-value"}},
+				},
+			},
+			expectedVersions: []*version{
+				{data: map[string]interface{}{"value": "old-value"}, deleted: true},
+				{data: map[string]interface{}{"value": "new-value"}, deleted: true},
+			},
+		},
+		{
+			description: "removes fields from shared secrets",
+			path:        "secret/data/team/shared?version=1#secret-name",
+			existing: map[string][]*version{
+				"secret/data/team/shared": {
+					{data: map[string]interface{}{"secret-name": "secret-value"}},
+					{data: map[string]interface{}{"secret-name": "secret-value", "other": "other-value"}},
+				},
+			},
+			expectedVersions: []*version{
+				{data: map[string]interface{}{"secret-name": "secret-value"}, deleted: true},
+				{data: map[string]interface{}{"secret-name": "secret-value", "other": "other-value"}},
+				{data: map[string]interface{}{"other": "other-value"}},
+			},
+		},
+		{
+			description: "keeps rotated fields in shared secrets",
+			path:        "secret/data/team/shared?version=1#secret-name",
+			existing: map[string][]*version{
+				"secret/data/team/shared": {
+					{data: map[string]interface{}{"secret-name": "old-value", "other": "other-value"}},
+					{data: map[string]interface{}{"secret-name": "new-value", "other": "other-value"}},
+				},
+			},
+			expectedVersions: []*version{
+				{data: map[string]interface{}{"secret-name": "old-value", "other": "other-value"}, deleted: true},
+				{data: map[string]interface{}{"secret-name": "new-value", "other": "other-value"}},
+			},
+		},
+		{
+			description:   "fails for invalid paths",
+			path:          "secret/team/secret-name",
+			expectedError: `invalid path: "secret/team/secret-name"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			secrets := tc.existing
+			if secrets == nil {
+				secrets = make(map[string][]*version)
+			}
+			err := vault.New(newFakeVaultAPI(secrets)).Delete(context.TODO(), tc.path, tc.config)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, versions := range secrets {
+				assert.Equal(t, tc.expectedVersions, versions)
+			}
+		})
+	}
+}
+
+func TestProcessRotation(t *testing.T) {
+	var (
+		secrets = make(map[string][]*version)
+		store   = vault.New(newFakeVaultAPI(secrets))
+		state   = sidecred.NewState()
+	)
+
+	// The rotation interval is shorter than the rotation window, so the credentials are rotated on every run.
+	s, err := sidecred.New([]sidecred.Provider{random.New(random.WithRotationInterval(time.Minute))}, []sidecred.SecretStore{store}, 10*time.Minute)
+	require.NoError(t, err)
+
+	cfg, err := config.Parse([]byte(strings.TrimSpace(`
+---
+version: 1
+namespace: team-name
+
+stores:
+- type: vault
+
+requests:
+- store: vault
+  creds:
+  - type: random
+    name: password
+    config:
+      length: 16
+	`)))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.Process(eventctx.TestContext(t), cfg, state))
+	}
+
+	// Previous versions are kept (and tracked in state) until they expire.
+	require.Len(t, state.Stores, 1)
+	secretState := state.Stores[0].Secrets
+	require.Len(t, secretState, 2)
+	assert.Equal(t, "secret/data/team-name/password?version=1#value", secretState[0].Path)
+	assert.True(t, secretState[0].Deposed)
+	assert.Equal(t, "secret/data/team-name/password?version=2#value", secretState[1].Path)
+	assert.False(t, secretState[1].Deposed)
+
+	value, found, err := store.Read(context.TODO(), secretState[0].Path, nil)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, secrets["secret/data/team-name/password"][0].data["value"], value)
+
+	// Expire the first version, which is deleted on the next run.
+	secretState[0].Expiration = time.Now().Add(-time.Minute)
+	require.NoError(t, s.Process(eventctx.TestContext(t), cfg, state))
+
+	var paths []string
+	for _, secret := range state.Stores[0].Secrets {
+		paths = append(paths, secret.Path)
+	}
+	assert.Equal(t, []string{
+		"secret/data/team-name/password?version=2#value",
+		"secret/data/team-name/password?version=3#value",
+	}, paths)
+
+	versions := secrets["secret/data/team-name/password"]
+	require.Len(t, versions, 3)
+	assert.True(t, versions[0].deleted, "expired version is deleted")
+	assert.False(t, versions[1].deleted, "previous version is kept")
+	assert.False(t, versions[2].deleted, "current version is kept")
+}